@@ -1,17 +1,27 @@
 package archery
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"math"
+	"math/big"
+	"slices"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/decimal256"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
 )
 
 // ARRAY AGGREGATION OPERATIONS
 
-// Sum returns the sum of all elements in the array
+// Sum returns the sum of all elements in the array. Decimal sums are exact and
+// returned as a decimal128.Num or decimal256.Num in the input's scale; a sum
+// that does not fit the type is an error.
 func Sum(ctx context.Context, input arrow.Array) (interface{}, error) {
 	// Implement sum manually since the compute function is not available
 	switch input.DataType().ID() {
@@ -116,6 +126,18 @@ func Sum(ctx context.Context, input arrow.Array) (interface{}, error) {
 			}
 		}
 		return sum, nil
+	case arrow.DECIMAL128:
+		sum := sumDecimals(input.(*array.Decimal128))
+		if sum.BitLen() > 127 {
+			return nil, fmt.Errorf("sum overflows %s", input.DataType())
+		}
+		return decimal128.FromBigInt(sum), nil
+	case arrow.DECIMAL256:
+		sum := sumDecimals(input.(*array.Decimal256))
+		if sum.BitLen() > 255 {
+			return nil, fmt.Errorf("sum overflows %s", input.DataType())
+		}
+		return decimal256.FromBigInt(sum), nil
 	default:
 		return nil, fmt.Errorf("sum not implemented for type %s", input.DataType())
 	}
@@ -155,7 +177,11 @@ func Mean(ctx context.Context, input arrow.Array) (float64, error) {
 	}
 }
 
-// Min returns the minimum value in the array
+// Min returns the minimum value in the array. Decimal values are returned as a
+// decimal128.Num or decimal256.Num in the input's scale. For Float64 arrays the
+// first non-null value seeds the scan and NaN never compares less, so the
+// result is NaN only when that first value is NaN; use MinWithOptions with
+// SkipNaN to ignore NaNs.
 func Min(ctx context.Context, input arrow.Array) (interface{}, error) {
 	// Implement min manually
 	if input.Len() == 0 || input.Len() == input.NullN() {
@@ -299,12 +325,18 @@ func Min(ctx context.Context, input arrow.Array) (interface{}, error) {
 			}
 		}
 		return min, nil
+	case arrow.DECIMAL128:
+		return extremeDecimal(input.(*array.Decimal128), false), nil
+	case arrow.DECIMAL256:
+		return extremeDecimal(input.(*array.Decimal256), false), nil
 	default:
 		return nil, fmt.Errorf("min not implemented for type %s", input.DataType())
 	}
 }
 
-// Max returns the maximum value in the array
+// Max returns the maximum value in the array. Decimal values are returned as a
+// decimal128.Num or decimal256.Num in the input's scale. Float64 NaNs behave as
+// in Min.
 func Max(ctx context.Context, input arrow.Array) (interface{}, error) {
 	// Implement max manually
 	if input.Len() == 0 || input.Len() == input.NullN() {
@@ -448,74 +480,490 @@ func Max(ctx context.Context, input arrow.Array) (interface{}, error) {
 			}
 		}
 		return max, nil
+	case arrow.DECIMAL128:
+		return extremeDecimal(input.(*array.Decimal128), true), nil
+	case arrow.DECIMAL256:
+		return extremeDecimal(input.(*array.Decimal256), true), nil
 	default:
 		return nil, fmt.Errorf("max not implemented for type %s", input.DataType())
 	}
 }
 
-// Mode returns the most common value in the array
+// sumDecimals returns the exact sum of the non-null values of a decimal array
+func sumDecimals[T interface{ BigInt() *big.Int }](arr valuer[T]) *big.Int {
+	sum := new(big.Int)
+	for i := 0; i < arr.Len(); i++ {
+		if !arr.IsNull(i) {
+			sum.Add(sum, arr.Value(i).BigInt())
+		}
+	}
+	return sum
+}
+
+// MinMaxOptions controls how MinWithOptions and MaxWithOptions treat floats
+type MinMaxOptions struct {
+	// SkipNaN ignores NaN values of Float32 and Float64 arrays like nulls, so
+	// the result is the extreme of the remaining values, or nil when there are
+	// none
+	SkipNaN bool
+}
+
+// MinWithOptions returns the minimum value in the array like Min, with NaN
+// handling set by the options. At most one options value may be given.
+func MinWithOptions(ctx context.Context, input arrow.Array, opts ...MinMaxOptions) (interface{}, error) {
+	return extremeWithOptions(ctx, input, false, opts)
+}
+
+// MaxWithOptions returns the maximum value in the array like Max, with NaN
+// handling set by the options. At most one options value may be given.
+func MaxWithOptions(ctx context.Context, input arrow.Array, opts ...MinMaxOptions) (interface{}, error) {
+	return extremeWithOptions(ctx, input, true, opts)
+}
+
+// extremeWithOptions backs MinWithOptions and MaxWithOptions
+func extremeWithOptions(ctx context.Context, input arrow.Array, largest bool, opts []MinMaxOptions) (interface{}, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	if len(opts) == 1 && opts[0].SkipNaN {
+		switch arr := input.(type) {
+		case *array.Float32:
+			return extremeNonNaN(arr, largest), nil
+		case *array.Float64:
+			return extremeNonNaN(arr, largest), nil
+		}
+	}
+	if largest {
+		return Max(ctx, input)
+	}
+	return Min(ctx, input)
+}
+
+// extremeNonNaN returns the smallest or largest value of a float array that is
+// neither null nor NaN, or nil if there is none
+func extremeNonNaN[T float32 | float64](arr floatArray[T], largest bool) interface{} {
+	var result T
+	found := false
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) || math.IsNaN(float64(arr.Value(i))) {
+			continue
+		}
+		v := arr.Value(i)
+		if !found || (largest && v > result) || (!largest && v < result) {
+			result = v
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return result
+}
+
+// extremeDecimal returns the smallest or largest non-null value of a decimal
+// array. The array must have at least one non-null value.
+func extremeDecimal[T interface{ Less(T) bool }](arr valuer[T], largest bool) T {
+	var result T
+	found := false
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		v := arr.Value(i)
+		if !found || (largest && result.Less(v)) || (!largest && v.Less(result)) {
+			result = v
+			found = true
+		}
+	}
+	return result
+}
+
+// Mode returns the most common value in the array. Ties are broken
+// deterministically by returning the smallest of the most common values.
 func Mode(ctx context.Context, input arrow.Array) (interface{}, error) {
-	// Implement mode manually
 	if input.Len() == 0 || input.Len() == input.NullN() {
 		return nil, nil
 	}
 
-	// For simplicity, we'll implement mode for a few common types
-	switch input.DataType().ID() {
-	case arrow.BOOL:
-		boolArr := input.(*array.Boolean)
-		trueCount := 0
-		falseCount := 0
-		for i := 0; i < boolArr.Len(); i++ {
-			if !boolArr.IsNull(i) {
-				if boolArr.Value(i) {
-					trueCount++
-				} else {
-					falseCount++
-				}
-			}
-		}
-		if trueCount > falseCount {
-			return true, nil
+	indices, _, err := modeIndices(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first index always points at the smallest modal value
+	idx := int(indices[0])
+	switch arr := input.(type) {
+	case *array.Boolean:
+		return arr.Value(idx), nil
+	case *array.Int8:
+		return arr.Value(idx), nil
+	case *array.Int16:
+		return arr.Value(idx), nil
+	case *array.Int32:
+		return arr.Value(idx), nil
+	case *array.Int64:
+		return arr.Value(idx), nil
+	case *array.Uint8:
+		return arr.Value(idx), nil
+	case *array.Uint16:
+		return arr.Value(idx), nil
+	case *array.Uint32:
+		return arr.Value(idx), nil
+	case *array.Uint64:
+		return arr.Value(idx), nil
+	case *array.Float32:
+		return arr.Value(idx), nil
+	case *array.Float64:
+		return arr.Value(idx), nil
+	case *array.String:
+		return arr.Value(idx), nil
+	default:
+		return nil, fmt.Errorf("mode not implemented for type %s", input.DataType())
+	}
+}
+
+// Modes returns every value tied for the highest frequency in ascending order,
+// along with that frequency. Null values are ignored.
+func Modes(ctx context.Context, input arrow.Array) (values arrow.Array, count int64, err error) {
+	indices, count, err := modeIndices(input)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues(indices, nil)
+	indicesArr := builder.NewArray()
+	defer indicesArr.Release()
+
+	values, err = TakeWithIndices(ctx, input, indicesArr)
+	if err != nil {
+		return nil, 0, err
+	}
+	return values, count, nil
+}
+
+// ModeBinned returns the range and count of the most populated of bins
+// equal-width histogram bins, as computed by Histogram. It gives a useful mode
+// for continuous data where exact values rarely repeat. Ties go to the lowest
+// bin.
+func ModeBinned(ctx context.Context, input arrow.Array, bins int) (rangeLow, rangeHigh float64, count int64, err error) {
+	edges, counts, err := Histogram(ctx, input, bins)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	best := 0
+	for i, c := range counts {
+		if c > counts[best] {
+			best = i
 		}
-		return false, nil
-	case arrow.INT64:
-		int64Arr := input.(*array.Int64)
-		counts := make(map[int64]int)
-		for i := 0; i < int64Arr.Len(); i++ {
-			if !int64Arr.IsNull(i) {
-				counts[int64Arr.Value(i)]++
+	}
+	return edges[best], edges[best+1], counts[best], nil
+}
+
+// valuer is implemented by the typed Arrow arrays that expose a Value accessor
+type valuer[T any] interface {
+	Len() int
+	IsNull(i int) bool
+	Value(i int) T
+}
+
+// modeIndices returns the index of the first occurrence of each modal value,
+// ordered by value, together with the modal frequency
+func modeIndices(input arrow.Array) ([]int64, int64, error) {
+	switch arr := input.(type) {
+	case *array.Boolean:
+		var counts [2]int64
+		first := [2]int64{-1, -1}
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				continue
 			}
-		}
-		var mode int64
-		maxCount := 0
-		for val, count := range counts {
-			if count > maxCount {
-				maxCount = count
-				mode = val
+			k := 0
+			if arr.Value(i) {
+				k = 1
 			}
-		}
-		return mode, nil
-	case arrow.FLOAT64:
-		float64Arr := input.(*array.Float64)
-		counts := make(map[float64]int)
-		for i := 0; i < float64Arr.Len(); i++ {
-			if !float64Arr.IsNull(i) {
-				counts[float64Arr.Value(i)]++
+			if first[k] < 0 {
+				first[k] = int64(i)
 			}
+			counts[k]++
 		}
-		var mode float64
-		maxCount := 0
-		for val, count := range counts {
-			if count > maxCount {
-				maxCount = count
-				mode = val
+		maxCount := max(counts[0], counts[1])
+		indices := []int64{}
+		for k := range counts {
+			if maxCount > 0 && counts[k] == maxCount {
+				indices = append(indices, first[k])
 			}
 		}
-		return mode, nil
+		return indices, maxCount, nil
+	case *array.Int8:
+		indices, count := orderedModeIndices[int8](arr)
+		return indices, count, nil
+	case *array.Int16:
+		indices, count := orderedModeIndices[int16](arr)
+		return indices, count, nil
+	case *array.Int32:
+		indices, count := orderedModeIndices[int32](arr)
+		return indices, count, nil
+	case *array.Int64:
+		indices, count := orderedModeIndices[int64](arr)
+		return indices, count, nil
+	case *array.Uint8:
+		indices, count := orderedModeIndices[uint8](arr)
+		return indices, count, nil
+	case *array.Uint16:
+		indices, count := orderedModeIndices[uint16](arr)
+		return indices, count, nil
+	case *array.Uint32:
+		indices, count := orderedModeIndices[uint32](arr)
+		return indices, count, nil
+	case *array.Uint64:
+		indices, count := orderedModeIndices[uint64](arr)
+		return indices, count, nil
+	case *array.Float32:
+		indices, count := orderedModeIndices[float32](arr)
+		return indices, count, nil
+	case *array.Float64:
+		indices, count := orderedModeIndices[float64](arr)
+		return indices, count, nil
+	case *array.String:
+		indices, count := orderedModeIndices[string](arr)
+		return indices, count, nil
 	default:
-		return nil, fmt.Errorf("mode not implemented for type %s", input.DataType())
+		return nil, 0, fmt.Errorf("mode not implemented for type %s", input.DataType())
+	}
+}
+
+// orderedModeIndices counts the non-null values of arr and returns the first
+// index of each value with the highest count, sorted by value
+func orderedModeIndices[T cmp.Ordered](arr valuer[T]) ([]int64, int64) {
+	type entry struct {
+		first int64
+		count int64
+	}
+
+	entries := make(map[T]entry)
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		v := arr.Value(i)
+		e, ok := entries[v]
+		if !ok {
+			e.first = int64(i)
+		}
+		e.count++
+		entries[v] = e
+	}
+
+	var maxCount int64
+	for _, e := range entries {
+		maxCount = max(maxCount, e.count)
+	}
+
+	modes := make([]int64, 0)
+	for _, e := range entries {
+		if e.count == maxCount {
+			modes = append(modes, e.first)
+		}
+	}
+	slices.SortFunc(modes, func(a, b int64) int {
+		return cmp.Compare(arr.Value(int(a)), arr.Value(int(b)))
+	})
+	return modes, maxCount
+}
+
+// GeometricMean returns the geometric mean of the array. All non-null values
+// must be strictly positive.
+func GeometricMean(ctx context.Context, input arrow.Array) (float64, error) {
+	values, err := nonNullFloat64s(input)
+	if err != nil {
+		return 0, fmt.Errorf("geometric mean: %w", err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	// Average the logarithms to avoid overflowing the product
+	var logSum float64
+	for _, v := range values {
+		if v <= 0 {
+			return 0, fmt.Errorf("geometric mean requires positive values, got %v", v)
+		}
+		logSum += math.Log(v)
+	}
+	return math.Exp(logSum / float64(len(values))), nil
+}
+
+// HarmonicMean returns the harmonic mean of the array. All non-null values
+// must be strictly positive.
+func HarmonicMean(ctx context.Context, input arrow.Array) (float64, error) {
+	values, err := nonNullFloat64s(input)
+	if err != nil {
+		return 0, fmt.Errorf("harmonic mean: %w", err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	var reciprocalSum float64
+	for _, v := range values {
+		if v <= 0 {
+			return 0, fmt.Errorf("harmonic mean requires positive values, got %v", v)
+		}
+		reciprocalSum += 1 / v
+	}
+	return float64(len(values)) / reciprocalSum, nil
+}
+
+// TrimmedMean returns the mean of the array after discarding the lowest and
+// highest proportion of the non-null values. Proportion must be in [0, 0.5).
+func TrimmedMean(ctx context.Context, input arrow.Array, proportion float64) (float64, error) {
+	if err := validateTrimProportion(proportion); err != nil {
+		return 0, err
+	}
+
+	values, err := nonNullFloat64s(input)
+	if err != nil {
+		return 0, fmt.Errorf("trimmed mean: %w", err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	// Drop the same number of values from each end of the sorted values
+	slices.Sort(values)
+	trim := int(float64(len(values)) * proportion)
+	kept := values[trim : len(values)-trim]
+
+	var sum float64
+	for _, v := range kept {
+		sum += v
+	}
+	return sum / float64(len(kept)), nil
+}
+
+// validateTrimProportion checks that a trim proportion is in [0, 0.5)
+func validateTrimProportion(proportion float64) error {
+	if !(proportion >= 0 && proportion < 0.5) {
+		return fmt.Errorf("trim proportion must be in [0, 0.5), got %v", proportion)
+	}
+	return nil
+}
+
+// WeightedMean returns the mean of values weighted by the parallel weights
+// array. Positions where either side is null are skipped. Weights must be
+// finite and non-negative, so NaN weights are an error, and must not sum to
+// zero.
+func WeightedMean(ctx context.Context, values, weights arrow.Array) (float64, error) {
+	if values.Len() != weights.Len() {
+		return 0, fmt.Errorf("arrays must have the same length, got %d and %d", values.Len(), weights.Len())
+	}
+
+	xs, err := castFloat64(ctx, values)
+	if err != nil {
+		return 0, fmt.Errorf("weighted mean: %w", err)
+	}
+	defer xs.Release()
+
+	ws, err := castFloat64(ctx, weights)
+	if err != nil {
+		return 0, fmt.Errorf("weighted mean: %w", err)
+	}
+	defer ws.Release()
+
+	var weightedSum, totalWeight float64
+	for i := 0; i < xs.Len(); i++ {
+		if xs.IsNull(i) || ws.IsNull(i) {
+			continue
+		}
+		w := ws.Value(i)
+		if !(w >= 0 && w <= math.MaxFloat64) {
+			return 0, fmt.Errorf("weighted mean requires finite non-negative weights, got %v", w)
+		}
+		weightedSum += w * xs.Value(i)
+		totalWeight += w
+	}
+	if totalWeight == 0 {
+		return 0, fmt.Errorf("weighted mean: total weight is zero")
+	}
+	return weightedSum / totalWeight, nil
+}
+
+// Quantile returns the q-th quantile of the non-null values of the array,
+// linearly interpolating between the two nearest ranks. Q must be in [0, 1].
+// An array without non-null values has no quantile and yields NaN, like an
+// empty TDigest.
+func Quantile(ctx context.Context, input arrow.Array, q float64) (float64, error) {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return 0, fmt.Errorf("quantile must be in [0, 1], got %v", q)
+	}
+
+	values, err := nonNullFloat64s(input)
+	if err != nil {
+		return 0, fmt.Errorf("quantile: %w", err)
+	}
+	if len(values) == 0 {
+		return math.NaN(), nil
+	}
+
+	slices.Sort(values)
+	return quantileSorted(values, q), nil
+}
+
+// quantileSorted returns the q-th quantile of already sorted values
+func quantileSorted(values []float64, q float64) float64 {
+	pos := q * float64(len(values)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	frac := pos - float64(lower)
+	return values[lower] + frac*(values[upper]-values[lower])
+}
+
+// nonNullFloat64s returns the non-null values of a numeric array as float64
+func nonNullFloat64s(input arrow.Array) ([]float64, error) {
+	values := make([]float64, 0, input.Len()-input.NullN())
+	switch arr := input.(type) {
+	case *array.Int8:
+		values = appendNonNull(values, arr)
+	case *array.Int16:
+		values = appendNonNull(values, arr)
+	case *array.Int32:
+		values = appendNonNull(values, arr)
+	case *array.Int64:
+		values = appendNonNull(values, arr)
+	case *array.Uint8:
+		values = appendNonNull(values, arr)
+	case *array.Uint16:
+		values = appendNonNull(values, arr)
+	case *array.Uint32:
+		values = appendNonNull(values, arr)
+	case *array.Uint64:
+		values = appendNonNull(values, arr)
+	case *array.Float32:
+		values = appendNonNull(values, arr)
+	case *array.Float64:
+		values = appendNonNull(values, arr)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", input.DataType())
 	}
+	return values, nil
+}
+
+// appendNonNull appends the non-null values of arr to dst as float64
+func appendNonNull[T number](dst []float64, arr valuer[T]) []float64 {
+	for i := 0; i < arr.Len(); i++ {
+		if !arr.IsNull(i) {
+			dst = append(dst, float64(arr.Value(i)))
+		}
+	}
+	return dst
+}
+
+// number is the set of Go types backing the numeric Arrow arrays
+type number interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
 }
 
 // Variance returns the variance of the array
@@ -613,6 +1061,83 @@ func StandardDeviation(ctx context.Context, input arrow.Array) (float64, error)
 	return math.Sqrt(variance), nil
 }
 
+// VarianceOptions controls the delta degrees of freedom of VarianceKernel and
+// StddevKernel
+type VarianceOptions struct {
+	// DDof is subtracted from the value count in the divisor: 0 gives the
+	// population variance and 1 the sample variance
+	DDof int
+}
+
+// VarianceKernel returns the variance of the non-null values with Arrow's
+// variance kernel semantics: the squared deviations are divided by N - DDof,
+// and the result is NaN where the kernel would return null, when N <= DDof.
+// The native kernel is used when the compute registry has one and otherwise a
+// two-pass manual computation; the two agree within floating point tolerance.
+// arrow-go v18.3 registers no variance kernel, so the manual path runs there.
+func VarianceKernel(ctx context.Context, input arrow.Array, opts ...VarianceOptions) (float64, error) {
+	if len(opts) > 1 {
+		return 0, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	var options VarianceOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+	if options.DDof < 0 {
+		return 0, fmt.Errorf("ddof must be non-negative, got %d", options.DDof)
+	}
+
+	n := input.Len() - input.NullN()
+	if n <= options.DDof {
+		return math.NaN(), nil
+	}
+
+	if _, ok := compute.GetFunctionRegistry().GetFunction("variance"); ok {
+		result, err := compute.CallFunction(ctx, "variance", nil, compute.NewDatum(input))
+		if err != nil && !kernelUnavailable(err) {
+			return 0, fmt.Errorf("failed to call variance: %w", err)
+		}
+		if err == nil {
+			// compute-upgraded
+			defer result.Release()
+			// The kernel defaults to ddof 0, so rescale its population variance
+			if datum, ok := result.(*compute.ScalarDatum); ok {
+				if s, ok := datum.Value.(*scalar.Float64); ok && s.IsValid() {
+					return s.Value * float64(n) / float64(n-options.DDof), nil
+				}
+			}
+		}
+	}
+	// compute.variance not registered or not implemented for the type – fallback
+	// TODO(archery): replace with compute.variance when supported
+	values, err := nonNullFloat64s(input)
+	if err != nil {
+		return 0, fmt.Errorf("variance: %w", err)
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(n)
+
+	var sumSquaredDiff float64
+	for _, v := range values {
+		diff := v - mean
+		sumSquaredDiff += diff * diff
+	}
+	return sumSquaredDiff / float64(n-options.DDof), nil
+}
+
+// StddevKernel returns the square root of VarianceKernel with the same options
+func StddevKernel(ctx context.Context, input arrow.Array, opts ...VarianceOptions) (float64, error) {
+	variance, err := VarianceKernel(ctx, input, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(variance), nil
+}
+
 // Count returns the number of non-null elements in the array
 func Count(ctx context.Context, input arrow.Array) (int64, error) {
 	// This is simply the length minus the null count
@@ -654,6 +1179,131 @@ func All(ctx context.Context, input arrow.Array) (bool, error) {
 	return true, nil
 }
 
+// TYPED AGGREGATION OPERATIONS
+
+// AggregateTyped asserts the interface{} result of an aggregation such as Sum,
+// Min, Max or Mode to T, passing errors through. A nil result, returned when
+// the array has no non-null values, is an error. It is meant to wrap a call
+// directly: AggregateTyped[float64](Max(ctx, arr)).
+func AggregateTyped[T any](result interface{}, err error) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, fmt.Errorf("aggregation has no result: array has no non-null values")
+	}
+	typed, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("aggregation result is %T, not %T", result, zero)
+	}
+	return typed, nil
+}
+
+// SumInt64 returns the sum of a signed integer or boolean array
+func SumInt64(ctx context.Context, input arrow.Array) (int64, error) {
+	return AggregateTyped[int64](Sum(ctx, input))
+}
+
+// SumFloat64 returns the sum of a floating point array
+func SumFloat64(ctx context.Context, input arrow.Array) (float64, error) {
+	return AggregateTyped[float64](Sum(ctx, input))
+}
+
+// MinInt64 returns the minimum value of an Int64 array
+func MinInt64(ctx context.Context, input arrow.Array) (int64, error) {
+	return AggregateTyped[int64](Min(ctx, input))
+}
+
+// MinFloat64 returns the minimum value of a Float64 array
+func MinFloat64(ctx context.Context, input arrow.Array) (float64, error) {
+	return AggregateTyped[float64](Min(ctx, input))
+}
+
+// MaxInt64 returns the maximum value of an Int64 array
+func MaxInt64(ctx context.Context, input arrow.Array) (int64, error) {
+	return AggregateTyped[int64](Max(ctx, input))
+}
+
+// MaxFloat64 returns the maximum value of a Float64 array
+func MaxFloat64(ctx context.Context, input arrow.Array) (float64, error) {
+	return AggregateTyped[float64](Max(ctx, input))
+}
+
+// AGGREGATORS
+
+// Aggregator reduces an array to a single value. Sum, Min, Max and Mode are Aggregators.
+// Functions that build records from the results, such as ColumnAggregates and
+// GroupTransform, accept Go integers, floats, bools, strings, decimals and nil,
+// and arrow.Array results, which become list cells and are released once
+// stored.
+type Aggregator func(ctx context.Context, input arrow.Array) (interface{}, error)
+
+// Float64Aggregator adapts a float64-valued reduction such as Mean, Variance or
+// StandardDeviation into an Aggregator
+func Float64Aggregator(fn func(ctx context.Context, input arrow.Array) (float64, error)) Aggregator {
+	return func(ctx context.Context, input arrow.Array) (interface{}, error) {
+		return fn(ctx, input)
+	}
+}
+
+// CountIfAggregator returns an Aggregator counting the non-null elements for
+// which the predicate returns true, as an int64. Predicates such as
+// EqualApprox work here, and with GroupTransform it counts per group, e.g.
+// passing scores per class, without dropping the failing rows first.
+func CountIfAggregator(predicate func(arr arrow.Array, i int) bool) Aggregator {
+	return func(ctx context.Context, input arrow.Array) (interface{}, error) {
+		indices, err := IndicesWhere(ctx, input, predicate)
+		if err != nil {
+			return nil, err
+		}
+		return int64(len(indices)), nil
+	}
+}
+
+// GeometricMeanAggregator returns an Aggregator computing GeometricMean, e.g.
+// the average growth ratio per group
+func GeometricMeanAggregator() Aggregator {
+	return Float64Aggregator(GeometricMean)
+}
+
+// HarmonicMeanAggregator returns an Aggregator computing HarmonicMean, e.g.
+// the average rate per group
+func HarmonicMeanAggregator() Aggregator {
+	return Float64Aggregator(HarmonicMean)
+}
+
+// TrimmedMeanAggregator returns an Aggregator computing TrimmedMean with the
+// given proportion, e.g. robust benchmark timings per group. The proportion
+// is validated here rather than once per group.
+func TrimmedMeanAggregator(proportion float64) (Aggregator, error) {
+	if err := validateTrimProportion(proportion); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, input arrow.Array) (interface{}, error) {
+		return TrimmedMean(ctx, input, proportion)
+	}, nil
+}
+
+// ListAggregator returns an Aggregator collecting the input's values, nulls
+// included, into an arrow.Array owned by the caller. With GroupByAuto or
+// GroupTransform it gathers each group's values into a list cell, e.g. every
+// event type per user. Aggregate returns the array itself, which the caller
+// must release.
+func ListAggregator() Aggregator {
+	return func(ctx context.Context, input arrow.Array) (interface{}, error) {
+		input.Retain()
+		return input, nil
+	}
+}
+
+// releaseResult releases an aggregation result that holds an array
+func releaseResult(result interface{}) {
+	if arr, ok := result.(arrow.Array); ok {
+		arr.Release()
+	}
+}
+
 // RECORD OPERATIONS
 
 // SumColumn returns the sum of a column in a record batch
@@ -722,6 +1372,23 @@ func StandardDeviationColumn(ctx context.Context, rec arrow.Record, colName stri
 	return StandardDeviation(ctx, col)
 }
 
+// WeightedMeanColumn returns the mean of a column weighted by another column
+func WeightedMeanColumn(ctx context.Context, rec arrow.Record, valueCol, weightCol string) (float64, error) {
+	values, err := GetColumn(rec, valueCol)
+	if err != nil {
+		return 0, err
+	}
+	defer ReleaseArray(values)
+
+	weights, err := GetColumn(rec, weightCol)
+	if err != nil {
+		return 0, err
+	}
+	defer ReleaseArray(weights)
+
+	return WeightedMean(ctx, values, weights)
+}
+
 // CountColumn returns the number of non-null elements in a column
 func CountColumn(ctx context.Context, rec arrow.Record, colName string) (int64, error) {
 	col, err := GetColumn(rec, colName)
@@ -732,3 +1399,175 @@ func CountColumn(ctx context.Context, rec arrow.Record, colName string) (int64,
 
 	return Count(ctx, col)
 }
+
+// Aggregate applies an aggregator to each named column of the record and returns
+// the results keyed by column name. Array results, as from ListAggregator, are
+// owned by the caller.
+func Aggregate(ctx context.Context, rec arrow.Record, specs map[string]Aggregator) (map[string]interface{}, error) {
+	results := make(map[string]interface{}, len(specs))
+	release := func() {
+		for _, result := range results {
+			releaseResult(result)
+		}
+	}
+	for colName, agg := range specs {
+		col, err := GetColumn(rec, colName)
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		result, err := agg(ctx, col)
+		col.Release()
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("error aggregating column %s: %w", colName, err)
+		}
+		results[colName] = result
+	}
+	return results, nil
+}
+
+// ColumnAggregates applies one aggregator to every numeric column of the
+// record and returns a one-row record of the results under the same column
+// names, such as a row of grand totals. Non-numeric columns are skipped. Each
+// result column takes the type of the aggregator's Go result, decimals keep
+// their input type, and a nil result becomes a column of the null type.
+func ColumnAggregates(ctx context.Context, rec arrow.Record, agg Aggregator) (arrow.Record, error) {
+	mem, _ := contextAllocator(ctx)
+	var fields []arrow.Field
+	var cols []arrow.Array
+	for i, field := range rec.Schema().Fields() {
+		if !isNumericType(field.Type) && !arrow.IsDecimal(field.Type.ID()) {
+			continue
+		}
+
+		result, err := agg(ctx, rec.Column(i))
+		if err != nil {
+			// Clean up already created columns
+			ReleaseArrays(cols...)
+			return nil, fmt.Errorf("error aggregating column %s: %w", field.Name, err)
+		}
+		col, err := resultArray(mem, result, field.Type)
+		releaseResult(result)
+		if err != nil {
+			ReleaseArrays(cols...)
+			return nil, fmt.Errorf("error aggregating column %s: %w", field.Name, err)
+		}
+		fields = append(fields, arrow.Field{Name: field.Name, Type: col.DataType(), Nullable: true})
+		cols = append(cols, col)
+	}
+
+	result := array.NewRecord(arrow.NewSchema(fields, nil), cols, 1)
+	ReleaseArrays(cols...)
+	return result, nil
+}
+
+// resultArray returns a one-element array allocated from mem holding an
+// aggregation result. Results must be a Go integer, float, bool or string, a
+// decimal of the input type, an array, which becomes a list, or nil for a null
+// result; any other type is an error. An array result is not released.
+func resultArray(mem memory.Allocator, value interface{}, inputType arrow.DataType) (arrow.Array, error) {
+	var sc scalar.Scalar
+	switch v := value.(type) {
+	case nil:
+		return array.MakeArrayOfNull(mem, arrow.Null, 1), nil
+	case int8:
+		sc = scalar.NewInt8Scalar(v)
+	case int16:
+		sc = scalar.NewInt16Scalar(v)
+	case int32:
+		sc = scalar.NewInt32Scalar(v)
+	case int64:
+		sc = scalar.NewInt64Scalar(v)
+	case int:
+		sc = scalar.NewInt64Scalar(int64(v))
+	case uint8:
+		sc = scalar.NewUint8Scalar(v)
+	case uint16:
+		sc = scalar.NewUint16Scalar(v)
+	case uint32:
+		sc = scalar.NewUint32Scalar(v)
+	case uint64:
+		sc = scalar.NewUint64Scalar(v)
+	case uint:
+		sc = scalar.NewUint64Scalar(uint64(v))
+	case float32:
+		sc = scalar.NewFloat32Scalar(v)
+	case float64:
+		sc = scalar.NewFloat64Scalar(v)
+	case bool:
+		sc = scalar.NewBooleanScalar(v)
+	case string:
+		sc = scalar.NewStringScalar(v)
+	case decimal128.Num:
+		sc = scalar.NewDecimal128Scalar(v, inputType)
+	case decimal256.Num:
+		sc = scalar.NewDecimal256Scalar(v, inputType)
+	case arrow.Array:
+		list := scalar.NewListScalar(v)
+		defer list.Release()
+		sc = list
+	default:
+		return nil, fmt.Errorf("unsupported aggregation result type %T", value)
+	}
+	return scalar.MakeArrayFromScalar(sc, 1, mem)
+}
+
+// NullCounts returns the number of null values in each column of the record
+func NullCounts(ctx context.Context, rec arrow.Record) (map[string]int64, error) {
+	counts := make(map[string]int64, rec.NumCols())
+	for i, col := range rec.Columns() {
+		counts[rec.ColumnName(i)] = CountNull(ctx, col)
+	}
+	return counts, nil
+}
+
+// Completeness returns the fraction of non-null values in each column of the
+// record. Columns of an empty record are reported as fully complete.
+func Completeness(ctx context.Context, rec arrow.Record) (map[string]float64, error) {
+	completeness := make(map[string]float64, rec.NumCols())
+	for i, col := range rec.Columns() {
+		completeness[rec.ColumnName(i)] = columnCompleteness(ctx, col)
+	}
+	return completeness, nil
+}
+
+// NullReport returns a record with one row per column of the input, holding the
+// column name, its null count and its completeness
+func NullReport(ctx context.Context, rec arrow.Record) (arrow.Record, error) {
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	countBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer countBuilder.Release()
+	completenessBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer completenessBuilder.Release()
+
+	for i, col := range rec.Columns() {
+		nameBuilder.Append(rec.ColumnName(i))
+		countBuilder.Append(CountNull(ctx, col))
+		completenessBuilder.Append(columnCompleteness(ctx, col))
+	}
+
+	names := nameBuilder.NewArray()
+	defer names.Release()
+	counts := countBuilder.NewArray()
+	defer counts.Release()
+	completeness := completenessBuilder.NewArray()
+	defer completeness.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "column", Type: arrow.BinaryTypes.String},
+		{Name: "null_count", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "completeness", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	return array.NewRecord(schema, []arrow.Array{names, counts, completeness}, int64(rec.NumCols())), nil
+}
+
+// columnCompleteness returns the fraction of non-null values in the array
+func columnCompleteness(ctx context.Context, col arrow.Array) float64 {
+	if col.Len() == 0 {
+		return 1
+	}
+	return 1 - float64(CountNull(ctx, col))/float64(col.Len())
+}
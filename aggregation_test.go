@@ -3,9 +3,13 @@ package archery_test
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
@@ -86,6 +90,62 @@ func Example_minMax() {
 	// Max: 5.0
 }
 
+func Example_minMaxSkipNaN() {
+	// Ratios where an upstream division produced NaN
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{math.NaN(), 0.4, 0.1, 0.9}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	ctx := context.Background()
+	min, err := archery.Min(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Min:", min)
+
+	// Ignore NaNs to get the extremes of the real values
+	opts := archery.MinMaxOptions{SkipNaN: true}
+	min, err = archery.MinWithOptions(ctx, arr, opts)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	max, err := archery.MaxWithOptions(ctx, arr, opts)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Min skipping NaN:", min)
+	fmt.Println("Max skipping NaN:", max)
+
+	// Float32 arrays skip NaNs the same way
+	builder32 := array.NewFloat32Builder(memory.DefaultAllocator)
+	defer builder32.Release()
+	builder32.AppendValues([]float32{0.5, float32(math.NaN()), 0.25}, nil)
+	arr32 := builder32.NewFloat32Array()
+	defer arr32.Release()
+	max, err = archery.MaxWithOptions(ctx, arr32, opts)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("Float32 max skipping NaN: %v (%T)\n", max, max)
+
+	// More than one options value is an error
+	_, err = archery.MinWithOptions(ctx, arr, opts, opts)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Min: NaN
+	// Min skipping NaN: 0.1
+	// Max skipping NaN: 0.9
+	// Float32 max skipping NaN: 0.5 (float32)
+	// Error: at most one options value may be given, got 2
+}
+
 func Example_variance() {
 	// Create a test array
 	builder := array.NewFloat64Builder(memory.DefaultAllocator)
@@ -132,6 +192,42 @@ func Example_standardDeviation() {
 	// Standard Deviation: 1.4
 }
 
+func Example_varianceKernel() {
+	// Create a test array
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1, 2, 3, 4, 5}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Population and sample statistics
+	ctx := context.Background()
+	population, err := archery.VarianceKernel(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	sample, err := archery.VarianceKernel(ctx, arr, archery.VarianceOptions{DDof: 1})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	sampleStd, err := archery.StddevKernel(ctx, arr, archery.VarianceOptions{DDof: 1})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Printf("Population variance: %.1f\n", population)
+	fmt.Printf("Sample variance: %.1f\n", sample)
+	fmt.Printf("Sample standard deviation: %.2f\n", sampleStd)
+
+	// Output:
+	// Population variance: 2.0
+	// Sample variance: 2.5
+	// Sample standard deviation: 1.58
+}
+
 func Example_count() {
 	// Create a test array with some null values
 	builder := array.NewFloat64Builder(memory.DefaultAllocator)
@@ -159,3 +255,640 @@ func Example_count() {
 	// Count: 4
 	// Null Count: 1
 }
+
+func Example_modes() {
+	// Create a test array with two equally common values
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{3, 1, 3, 2, 1, 4}, nil)
+	arr := builder.NewInt64Array()
+	defer arr.Release()
+
+	// Find all modes
+	ctx := context.Background()
+	modes, count, err := archery.Modes(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(modes)
+
+	// Mode breaks ties by picking the smallest value
+	mode, err := archery.Mode(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the results
+	fmt.Println("Modes:", modes.(*array.Int64).Int64Values())
+	fmt.Println("Count:", count)
+	fmt.Println("Mode:", mode)
+
+	// Output:
+	// Modes: [1 3]
+	// Count: 2
+	// Mode: 1
+}
+
+func Example_modeBinned() {
+	// Continuous temperature readings where no value repeats
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{18.2, 21.4, 21.9, 22.3, 20.6, 23.7, 21.1, 26.0}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Find the most common range among four bins
+	ctx := context.Background()
+	low, high, count, err := archery.ModeBinned(ctx, arr, 4)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Printf("Most common range: [%.2f, %.2f) with %d readings\n", low, high, count)
+
+	// Output:
+	// Most common range: [20.15, 22.10) with 4 readings
+}
+
+func Example_alternativeMeans() {
+	// Create a test array of growth ratios
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1, 2, 4}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Calculate geometric and harmonic means
+	ctx := context.Background()
+	geo, err := archery.GeometricMean(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	harmonic, err := archery.HarmonicMean(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the results
+	fmt.Printf("Geometric Mean: %.2f\n", geo)
+	fmt.Printf("Harmonic Mean: %.2f\n", harmonic)
+
+	// The same means per fund, through their aggregators
+	fundBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer fundBuilder.Release()
+	fundBuilder.AppendValues([]string{"a", "b", "a", "b", "a"}, nil)
+	funds := fundBuilder.NewArray()
+	defer funds.Release()
+
+	ratioBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer ratioBuilder.Release()
+	ratioBuilder.AppendValues([]float64{1, 3, 2, 12, 4}, nil)
+	ratios := ratioBuilder.NewArray()
+	defer ratios.Release()
+
+	rec, err := archery.ZipArrays([]string{"fund", "ratio", "rate"}, []arrow.Array{funds, ratios, ratios})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	perFund, err := archery.GroupByAuto(ctx, rec, []string{"fund"}, map[string]archery.Aggregator{
+		"ratio": archery.GeometricMeanAggregator(),
+		"rate":  archery.HarmonicMeanAggregator(),
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer perFund.Release()
+	table, err := archery.FormatRecord(perFund, archery.FormatOptions{FloatPrecision: 2})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(table)
+
+	// Output:
+	// Geometric Mean: 2.00
+	// Harmonic Mean: 1.71
+	// fund  ratio  rate  count
+	// a     2.00   1.71  3
+	// b     6.00   4.80  2
+}
+
+func Example_trimmedMean() {
+	// Create a test array with an outlier
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{10, 11, 12, 13, 100}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Discard the lowest and highest 20% before averaging
+	ctx := context.Background()
+	mean, err := archery.TrimmedMean(ctx, arr, 0.2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the result
+	fmt.Printf("Trimmed Mean: %.1f\n", mean)
+
+	// The same trimming per benchmark, through its aggregator
+	benchBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer benchBuilder.Release()
+	benchBuilder.AppendValues([]string{"x", "x", "x", "x", "x", "y", "y", "y", "y", "y"}, nil)
+	benches := benchBuilder.NewArray()
+	defer benches.Release()
+
+	timingBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer timingBuilder.Release()
+	timingBuilder.AppendValues([]float64{10, 11, 12, 13, 100, 1, 5, 6, 7, 8}, nil)
+	timings := timingBuilder.NewArray()
+	defer timings.Release()
+
+	rec, err := archery.ZipArrays([]string{"bench", "timing"}, []arrow.Array{benches, timings})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	trimmed, err := archery.TrimmedMeanAggregator(0.2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	perBench, err := archery.GroupByAuto(ctx, rec, []string{"bench"}, map[string]archery.Aggregator{"timing": trimmed})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer perBench.Release()
+	fmt.Println("Per benchmark:", perBench.Column(0), perBench.Column(1))
+
+	// Invalid proportions are rejected when the aggregator is built
+	_, err = archery.TrimmedMeanAggregator(math.NaN())
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Trimmed Mean: 12.0
+	// Per benchmark: ["x" "y"] [12 6]
+	// Error: trim proportion must be in [0, 0.5), got NaN
+}
+
+func Example_weightedMean() {
+	// Create prices and traded volumes
+	priceBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer priceBuilder.Release()
+	priceBuilder.AppendValues([]float64{10, 11, 12}, nil)
+	prices := priceBuilder.NewFloat64Array()
+	defer prices.Release()
+
+	volumeBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer volumeBuilder.Release()
+	volumeBuilder.AppendValues([]float64{100, 300, 0}, []bool{true, true, false})
+	volumes := volumeBuilder.NewFloat64Array()
+	defer volumes.Release()
+
+	// The null volume skips its price
+	ctx := context.Background()
+	vwap, err := archery.WeightedMean(ctx, prices, volumes)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("VWAP:", vwap)
+
+	// NaN weights are rejected rather than poisoning the mean
+	volumeBuilder.AppendValues([]float64{100, math.NaN(), 50}, nil)
+	badVolumes := volumeBuilder.NewFloat64Array()
+	defer badVolumes.Release()
+	_, err = archery.WeightedMean(ctx, prices, badVolumes)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// VWAP: 10.75
+	// Error: weighted mean requires finite non-negative weights, got NaN
+}
+
+func Example_quantile() {
+	// Create a test array
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, nil)
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Compute the median and the 90th percentile
+	ctx := context.Background()
+	median, err := archery.Quantile(ctx, arr, 0.5)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	p90, err := archery.Quantile(ctx, arr, 0.9)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the results
+	fmt.Printf("Median: %.1f\n", median)
+	fmt.Printf("P90: %.1f\n", p90)
+
+	// Without non-null values there is no quantile
+	builder.AppendNulls(3)
+	empty := builder.NewArray()
+	defer empty.Release()
+	none, err := archery.Quantile(ctx, empty, 0.5)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Median of nulls:", none)
+
+	// Output:
+	// Median: 5.5
+	// P90: 9.1
+	// Median of nulls: NaN
+}
+
+func Example_aggregate() {
+	// Create a test record
+	revenueBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer revenueBuilder.Release()
+	revenueBuilder.AppendValues([]float64{100, 250, 50}, nil)
+	revenue := revenueBuilder.NewArray()
+	defer revenue.Release()
+
+	latencyBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer latencyBuilder.Release()
+	latencyBuilder.AppendValues([]int64{12, 30, 18}, nil)
+	latency := latencyBuilder.NewArray()
+	defer latency.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "revenue", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "latency", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{revenue, latency}, 3)
+	defer rec.Release()
+
+	// Compute different statistics for each column
+	ctx := context.Background()
+	results, err := archery.Aggregate(ctx, rec, map[string]archery.Aggregator{
+		"revenue": archery.Sum,
+		"latency": archery.Float64Aggregator(archery.Mean),
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the results
+	fmt.Printf("Revenue sum: %.1f\n", results["revenue"])
+	fmt.Printf("Latency mean: %.1f\n", results["latency"])
+
+	// Output:
+	// Revenue sum: 400.0
+	// Latency mean: 20.0
+}
+
+func Example_nullReport() {
+	// Create a test record with missing values
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{1, 2, 3, 4}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	emailBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer emailBuilder.Release()
+	emailBuilder.AppendValues([]string{"a@x.io", "", "c@x.io", ""}, []bool{true, false, true, false})
+	emails := emailBuilder.NewArray()
+	defer emails.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "email", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids, emails}, 4)
+	defer rec.Release()
+
+	// Check the completeness of each column
+	ctx := context.Background()
+	completeness, err := archery.Completeness(ctx, rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Email completeness:", completeness["email"])
+
+	// Build a report record
+	report, err := archery.NullReport(ctx, rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(report)
+
+	fmt.Println("Columns:", report.Column(0))
+	fmt.Println("Null counts:", report.Column(1))
+
+	// Output:
+	// Email completeness: 0.5
+	// Columns: ["id" "email"]
+	// Null counts: [0 2]
+}
+
+func Example_aggregateTyped() {
+	// Create a test array
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{2.5, 7.25, 1.0}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Typed conveniences avoid asserting interface{} results
+	ctx := context.Background()
+	total, err := archery.SumFloat64(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// AggregateTyped wraps any interface{} aggregation
+	mode, err := archery.AggregateTyped[float64](archery.Mode(ctx, arr))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Asking for the wrong type is an error rather than a panic
+	_, err = archery.MaxInt64(ctx, arr)
+
+	// Print the results
+	fmt.Println("Sum:", total)
+	fmt.Println("Mode:", mode)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Sum: 10.75
+	// Mode: 1
+	// Error: aggregation result is float64, not int64
+}
+
+func Example_weightedMeanColumn() {
+	// Create a record of trades
+	priceBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer priceBuilder.Release()
+	priceBuilder.AppendValues([]float64{10, 11, 12}, nil)
+	prices := priceBuilder.NewArray()
+	defer prices.Release()
+
+	volumeBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer volumeBuilder.Release()
+	volumeBuilder.AppendValues([]int64{100, 300, 100}, nil)
+	volumes := volumeBuilder.NewArray()
+	defer volumes.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "volume", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{prices, volumes}, 3)
+	defer rec.Release()
+
+	// Compute the volume-weighted average price
+	ctx := context.Background()
+	vwap, err := archery.WeightedMeanColumn(ctx, rec, "price", "volume")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the result
+	fmt.Printf("VWAP: %.2f\n", vwap)
+
+	// Output:
+	// VWAP: 11.00
+}
+
+func Example_decimalSum() {
+	// Create a decimal array of monetary amounts with two decimal places
+	dt := &arrow.Decimal128Type{Precision: 10, Scale: 2}
+	builder := array.NewDecimal128Builder(memory.DefaultAllocator, dt)
+	defer builder.Release()
+	for _, cents := range []int64{110, 220, 5} {
+		builder.Append(decimal128.FromI64(cents))
+	}
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Sum exactly, without a lossy cast to float
+	ctx := context.Background()
+	sum, err := archery.AggregateTyped[decimal128.Num](archery.Sum(ctx, arr))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	largest, err := archery.AggregateTyped[decimal128.Num](archery.Max(ctx, arr))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the results in the input's scale
+	fmt.Println("Sum:", sum.ToString(dt.Scale))
+	fmt.Println("Max:", largest.ToString(dt.Scale))
+
+	// Output:
+	// Sum: 3.35
+	// Max: 2.20
+}
+
+func Example_columnAggregates() {
+	// Create a sales record with a label column
+	regionBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer regionBuilder.Release()
+	regionBuilder.AppendValues([]string{"north", "south", "east"}, nil)
+	regions := regionBuilder.NewArray()
+	defer regions.Release()
+
+	unitsBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer unitsBuilder.Release()
+	unitsBuilder.AppendValues([]int64{12, 30, 8}, nil)
+	units := unitsBuilder.NewArray()
+	defer units.Release()
+
+	revenueBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer revenueBuilder.Release()
+	revenueBuilder.AppendValues([]float64{120.5, 310, 79.5}, nil)
+	revenue := revenueBuilder.NewArray()
+	defer revenue.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String},
+		{Name: "units", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "revenue", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{regions, units, revenue}, 3)
+	defer rec.Release()
+
+	// Build a grand totals row and a row of means
+	ctx := context.Background()
+	totals, err := archery.ColumnAggregates(ctx, rec, archery.Sum)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer totals.Release()
+
+	means, err := archery.ColumnAggregates(ctx, rec, archery.Float64Aggregator(archery.Mean))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer means.Release()
+
+	fmt.Println("Columns:", archery.ColumnNames(totals))
+	fmt.Println("Totals:", totals.Column(0), totals.Column(1))
+	fmt.Println("Means:", means.Column(0), means.Column(1))
+
+	// Output:
+	// Columns: [units revenue]
+	// Totals: [50] [510]
+	// Means: [16.666666666666668] [170]
+}
+
+func Example_columnAggregatesUnsupportedResult() {
+	// Create a record of request latencies in milliseconds
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{120, 80, 100}, nil)
+	latency := builder.NewArray()
+	defer latency.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "latency", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{latency}, 3)
+	defer rec.Release()
+
+	// An aggregator returning a time.Duration has no Arrow result type
+	total := func(ctx context.Context, input arrow.Array) (interface{}, error) {
+		sum, err := archery.SumInt64(ctx, input)
+		return time.Duration(sum) * time.Millisecond, err
+	}
+
+	ctx := context.Background()
+	_, err := archery.ColumnAggregates(ctx, rec, total)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Error: error aggregating column latency: unsupported aggregation result type time.Duration
+}
+
+func Example_countIfAggregator() {
+	// Create a test record of scores per section
+	sectionBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer sectionBuilder.Release()
+	sectionBuilder.AppendValues([]string{"s1", "s2", "s1", "s1", "s2"}, nil)
+	sections := sectionBuilder.NewArray()
+	defer sections.Release()
+
+	scoreBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]int64{72, 40, 55, 90, 0}, []bool{true, true, true, true, false})
+	scores := scoreBuilder.NewArray()
+	defer scores.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "section", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{sections, scores}, 5)
+	defer rec.Release()
+
+	// Count passing scores per section, keeping every row
+	passing := archery.CountIfAggregator(func(arr arrow.Array, i int) bool {
+		return arr.(*array.Int64).Value(i) >= 60
+	})
+	ctx := context.Background()
+	withCounts, err := archery.GroupTransform(ctx, rec, []string{"section"}, "score", passing)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer withCounts.Release()
+	fmt.Println("Passing in section:", withCounts.Column(2))
+
+	// Output:
+	// Passing in section: [2 0 2 2 0]
+}
+
+func Example_listAggregator() {
+	// Create a record of events per user
+	userBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer userBuilder.Release()
+	userBuilder.AppendValues([]string{"ann", "bob", "ann", "ann", "bob"}, nil)
+	users := userBuilder.NewArray()
+	defer users.Release()
+
+	eventBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer eventBuilder.Release()
+	eventBuilder.AppendValues([]string{"login", "view", "", "logout", "login"}, []bool{true, true, false, true, true})
+	events := eventBuilder.NewArray()
+	defer events.Release()
+
+	rec, err := archery.ZipArrays([]string{"user", "event"}, []arrow.Array{users, events})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	// Collect every event of each user into a list, tracking the memory used
+	mem := archery.NewBoundedAllocator(nil, 1<<20)
+	ctx := archery.WithAllocator(context.Background(), mem)
+	perUser, err := archery.GroupByAuto(ctx, rec, []string{"user"}, map[string]archery.Aggregator{
+		"event": archery.ListAggregator(),
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Users:", perUser.Column(0))
+	fmt.Println("Events:", perUser.Column(1))
+	fmt.Println("Type:", perUser.Column(1).DataType())
+	perUser.Release()
+	fmt.Println("Outstanding after release:", mem.Allocated())
+
+	// Aggregate hands back the array itself
+	results, err := archery.Aggregate(context.Background(), rec, map[string]archery.Aggregator{
+		"event": archery.ListAggregator(),
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	all := results["event"].(arrow.Array)
+	defer all.Release()
+	fmt.Println("All events:", all)
+
+	// Output:
+	// Users: ["ann" "bob"]
+	// Events: [["login" (null) "logout"] ["view" "login"]]
+	// Type: list<item: utf8, nullable>
+	// Outstanding after release: 0
+	// All events: ["login" "view" (null) "logout" "login"]
+}
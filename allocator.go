@@ -0,0 +1,113 @@
+package archery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// MEMORY LIMITS
+
+// ErrMemoryLimitExceeded is wrapped by the panic value of a BoundedAllocator
+// allocation that would exceed its limit
+var ErrMemoryLimitExceeded = errors.New("memory limit exceeded")
+
+// BoundedAllocator is a memory.Allocator that caps the bytes outstanding in
+// another allocator. The Allocator interface cannot return errors, so an
+// allocation over the limit panics with an error wrapping
+// ErrMemoryLimitExceeded; run the operation through SafeCall to get it back
+// as an error, which errors.Is matches against ErrMemoryLimitExceeded.
+//
+// Use it with functions that take an allocator, such as IndicesToMask, with
+// contexts from WithAllocator, with CSVOptions.Allocator, with array builders,
+// and with readers such as ipc.NewReader via ipc.WithAllocator. Do not place
+// it in a compute context with compute.WithAllocator: kernels run on their own
+// goroutines, where the panic cannot be recovered.
+type BoundedAllocator struct {
+	mem       memory.Allocator
+	limit     int64
+	allocated atomic.Int64
+}
+
+// NewBoundedAllocator returns an allocator that allows at most limit bytes to
+// be outstanding in mem, or in the default allocator when mem is nil
+func NewBoundedAllocator(mem memory.Allocator, limit int64) *BoundedAllocator {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	return &BoundedAllocator{mem: mem, limit: limit}
+}
+
+// Allocate allocates size bytes, panicking if that would exceed the limit
+func (a *BoundedAllocator) Allocate(size int) []byte {
+	a.reserve(int64(size))
+	return a.mem.Allocate(size)
+}
+
+// Reallocate resizes b to size bytes, panicking if growing would exceed the limit
+func (a *BoundedAllocator) Reallocate(size int, b []byte) []byte {
+	a.reserve(int64(size - len(b)))
+	return a.mem.Reallocate(size, b)
+}
+
+// Free releases b back to the wrapped allocator
+func (a *BoundedAllocator) Free(b []byte) {
+	a.allocated.Add(-int64(len(b)))
+	a.mem.Free(b)
+}
+
+// Allocated returns the number of bytes currently outstanding
+func (a *BoundedAllocator) Allocated() int64 {
+	return a.allocated.Load()
+}
+
+// Limit returns the maximum number of bytes that may be outstanding
+func (a *BoundedAllocator) Limit() int64 {
+	return a.limit
+}
+
+// reserve accounts for delta more bytes, undoing it and panicking when the
+// total would exceed the limit
+func (a *BoundedAllocator) reserve(delta int64) {
+	total := a.allocated.Add(delta)
+	if delta > 0 && total > a.limit {
+		a.allocated.Add(-delta)
+		panic(fmt.Errorf("%w: allocating %d bytes would bring usage to %d of %d", ErrMemoryLimitExceeded, delta, total, a.limit))
+	}
+}
+
+// allocatorKey is the context key under which WithAllocator stores an allocator
+type allocatorKey struct{}
+
+// WithAllocator returns a context under which the functions that copy or
+// gather data allocate their results from mem instead of the default
+// allocator. They then allocate on the calling goroutine, so a
+// BoundedAllocator over its limit is recovered by SafeCall. The functions
+// that honour it are:
+//
+//   - TakeWithIndices, TakeRecord, CrossJoin, RecordDiff and MergeSorted
+//   - SplitRecordByColumn, TopNPerGroup, ArgMaxPerGroup, ArgMinPerGroup,
+//     GroupTransform, GroupByAuto and WeightedMeanPerGroup
+//   - ColumnAggregates, Compact, EqualNullSafe, Invert, Where, WhereScalar and
+//     CaseWhen
+//
+// Other functions still allocate from the default allocator, and the
+// allocator is never handed to compute kernels, unlike compute.WithAllocator.
+// Rows are gathered this way for booleans, fixed-width types such as numbers,
+// decimals, dates and timestamps, strings and binary, with any integer
+// indices; other types fall back to the take kernel and the default allocator.
+func WithAllocator(ctx context.Context, mem memory.Allocator) context.Context {
+	return context.WithValue(ctx, allocatorKey{}, mem)
+}
+
+// contextAllocator returns the allocator stored by WithAllocator and true, or
+// the default allocator and false when there is none
+func contextAllocator(ctx context.Context) (memory.Allocator, bool) {
+	if mem, ok := ctx.Value(allocatorKey{}).(memory.Allocator); ok && mem != nil {
+		return mem, true
+	}
+	return memory.DefaultAllocator, false
+}
@@ -0,0 +1,213 @@
+package archery_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_boundedAllocator() {
+	// Cap outstanding memory at 1 KiB
+	mem := archery.NewBoundedAllocator(nil, 1024)
+
+	// Small allocations within the budget succeed
+	mask, err := archery.IndicesToMask([]int64{1, 3}, 8, mem)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Mask:", mask)
+	mask.Release()
+	fmt.Println("Outstanding after release:", mem.Allocated())
+
+	// A mask of a million rows needs more, so it fails instead of growing unbounded
+	_, err = archery.SafeCall(func() (*array.Boolean, error) {
+		return archery.IndicesToMask(nil, 1_000_000, mem)
+	})
+	fmt.Println("Limit exceeded:", errors.Is(err, archery.ErrMemoryLimitExceeded))
+
+	// Output:
+	// Mask: [false true false true false false false false]
+	// Outstanding after release: 0
+	// Limit exceeded: true
+}
+
+func Example_withAllocator() {
+	// Create a small record of ids and names
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{1, 2, 3}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"a", "", "c"}, []bool{true, false, true})
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	rec, err := archery.ZipArrays([]string{"id", "name"}, []arrow.Array{ids, names})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	// Record operations under this context allocate from a 4 KiB budget
+	mem := archery.NewBoundedAllocator(nil, 4096)
+	ctx := archery.WithAllocator(context.Background(), mem)
+
+	indexBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer indexBuilder.Release()
+	indexBuilder.AppendValues([]int64{2, 0, 1}, nil)
+	indices := indexBuilder.NewArray()
+	defer indices.Release()
+
+	taken, err := archery.TakeRecord(ctx, rec, indices)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Taken:", taken.Column(0), taken.Column(1))
+	fmt.Println("Allocated from budget:", mem.Allocated() > 0)
+	taken.Release()
+	fmt.Println("Outstanding after release:", mem.Allocated())
+
+	// A cross join with 1,000 rows needs more than the budget allows
+	otherBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer otherBuilder.Release()
+	for i := 0; i < 1000; i++ {
+		otherBuilder.Append(int64(i))
+	}
+	other := otherBuilder.NewArray()
+	defer other.Release()
+
+	otherRec, err := archery.ZipArrays([]string{"other"}, []arrow.Array{other})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer otherRec.Release()
+
+	_, err = archery.SafeCall(func() (arrow.Record, error) {
+		return archery.CrossJoin(ctx, rec, otherRec)
+	})
+	fmt.Println("Limit exceeded:", errors.Is(err, archery.ErrMemoryLimitExceeded))
+
+	// Columns built before the limit was hit are released again
+	fmt.Println("Outstanding after failure:", mem.Allocated())
+
+	// Output:
+	// Taken: [3 1 2] ["c" "a" (null)]
+	// Allocated from budget: true
+	// Outstanding after release: 0
+	// Limit exceeded: true
+	// Outstanding after failure: 0
+}
+
+func Example_withAllocatorPartialFailure() {
+	// Create a record of two 1,000-row columns
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	for i := 0; i < 1000; i++ {
+		builder.Append(int64(i))
+	}
+	a := builder.NewArray()
+	defer a.Release()
+	for i := 0; i < 1000; i++ {
+		builder.Append(int64(-i))
+	}
+	b := builder.NewArray()
+	defer b.Release()
+
+	rec, err := archery.ZipArrays([]string{"a", "b"}, []arrow.Array{a, b})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	indexBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer indexBuilder.Release()
+	for i := 999; i >= 0; i-- {
+		indexBuilder.Append(int64(i))
+	}
+	indices := indexBuilder.NewArray()
+	defer indices.Release()
+
+	// The budget holds the first taken column but not the second
+	mem := archery.NewBoundedAllocator(nil, 10000)
+	ctx := archery.WithAllocator(context.Background(), mem)
+	_, err = archery.SafeCall(func() (arrow.Record, error) {
+		return archery.TakeRecord(ctx, rec, indices)
+	})
+	fmt.Println("Limit exceeded:", errors.Is(err, archery.ErrMemoryLimitExceeded))
+	fmt.Println("Outstanding after failure:", mem.Allocated())
+
+	// Output:
+	// Limit exceeded: true
+	// Outstanding after failure: 0
+}
+
+func Example_withAllocatorFallback() {
+	// Create a large string column and a list column
+	largeBuilder := array.NewLargeStringBuilder(memory.DefaultAllocator)
+	defer largeBuilder.Release()
+	largeBuilder.AppendValues([]string{"x", "y", "z"}, nil)
+	large := largeBuilder.NewArray()
+	defer large.Release()
+
+	listBuilder := array.NewListBuilder(memory.DefaultAllocator, arrow.PrimitiveTypes.Int64)
+	defer listBuilder.Release()
+	values := listBuilder.ValueBuilder().(*array.Int64Builder)
+	for _, row := range [][]int64{{1}, {2, 3}, {}} {
+		listBuilder.Append(true)
+		values.AppendValues(row, nil)
+	}
+	list := listBuilder.NewArray()
+	defer list.Release()
+
+	// Int32 indices are widened rather than rejected
+	indexBuilder := array.NewInt32Builder(memory.DefaultAllocator)
+	defer indexBuilder.Release()
+	indexBuilder.AppendValues([]int32{2, 0, 1}, nil)
+	indices := indexBuilder.NewArray()
+	defer indices.Release()
+
+	mem := archery.NewBoundedAllocator(nil, 4096)
+	ctx := archery.WithAllocator(context.Background(), mem)
+
+	// Types the allocator path cannot copy are taken by the kernel instead
+	for _, input := range []arrow.Array{large, list} {
+		taken, err := archery.TakeWithIndices(ctx, input, indices)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Println("Taken:", taken)
+		taken.Release()
+	}
+
+	// Supported types still use the budget, whatever the index type
+	ints, err := archery.TakeWithIndices(ctx, indices, indices)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Taken:", ints)
+	fmt.Println("Allocated from budget:", mem.Allocated() > 0)
+	ints.Release()
+	fmt.Println("Outstanding after release:", mem.Allocated())
+
+	// Output:
+	// Taken: ["z" "x" "y"]
+	// Taken: [[] [1] [2 3]]
+	// Taken: [1 2 0]
+	// Allocated from budget: true
+	// Outstanding after release: 0
+}
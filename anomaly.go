@@ -3,14 +3,19 @@ package archery
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/arrow-go/v18/arrow/scalar"
 )
 
-// AnomalyResult holds mask and z-scores for anomalies.
+// AnomalyResult holds mask and z-scores for anomalies. Detectors that do not
+// use z-scores document what Zscore carries instead.
 type AnomalyResult struct {
 	Mask   *array.Boolean
 	Zscore *array.Float64
@@ -109,3 +114,145 @@ func DetectAnomalies(ctx context.Context, col arrow.Array, threshold float64) (*
 
 	return &AnomalyResult{Mask: maskArr, Zscore: zArr}, nil
 }
+
+// DetectAnomaliesPercentile flags values outside the [lowerPct, upperPct]
+// percentile band of the non-null values, with both bounds in [0, 100] and
+// interpolated as in Quantile. It makes no assumption about the distribution.
+// Instead of a z-score, Zscore carries each value's percentile rank in
+// [0, 100]: its position in sorted order scaled to the band's units, averaged
+// over ties. Nulls yield nulls in both arrays.
+func DetectAnomaliesPercentile(ctx context.Context, col arrow.Array, lowerPct, upperPct float64) (*AnomalyResult, error) {
+	if lowerPct < 0 || upperPct > 100 || !(lowerPct <= upperPct) {
+		return nil, fmt.Errorf("percentile band must satisfy 0 <= lower <= upper <= 100, got [%v, %v]", lowerPct, upperPct)
+	}
+
+	values, err := castFloat64(ctx, col)
+	if err != nil {
+		return nil, err
+	}
+	defer values.Release()
+
+	sorted, err := nonNullFloat64s(values)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(sorted)
+
+	maskBuilder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer maskBuilder.Release()
+	rankBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer rankBuilder.Release()
+	maskBuilder.Reserve(values.Len())
+	rankBuilder.Reserve(values.Len())
+
+	if len(sorted) == 0 {
+		for i := 0; i < values.Len(); i++ {
+			maskBuilder.AppendNull()
+			rankBuilder.AppendNull()
+		}
+		return &AnomalyResult{Mask: maskBuilder.NewBooleanArray(), Zscore: rankBuilder.NewFloat64Array()}, nil
+	}
+
+	lower := quantileSorted(sorted, lowerPct/100)
+	upper := quantileSorted(sorted, upperPct/100)
+	for i := 0; i < values.Len(); i++ {
+		if values.IsNull(i) {
+			maskBuilder.AppendNull()
+			rankBuilder.AppendNull()
+			continue
+		}
+		v := values.Value(i)
+		maskBuilder.Append(v < lower || v > upper)
+		rankBuilder.Append(percentRank(sorted, v))
+	}
+
+	return &AnomalyResult{Mask: maskBuilder.NewBooleanArray(), Zscore: rankBuilder.NewFloat64Array()}, nil
+}
+
+// percentRank returns the percentile rank of v within sorted values: the mean
+// sorted position of v's ties divided by the last position, times 100
+func percentRank(sorted []float64, v float64) float64 {
+	if len(sorted) == 1 || math.IsNaN(v) {
+		return 0
+	}
+	first := sort.SearchFloat64s(sorted, v)
+	end := sort.Search(len(sorted), func(i int) bool { return sorted[i] > v })
+	pos := float64(first+end-1) / 2
+	return 100 * pos / float64(len(sorted)-1)
+}
+
+// DetectAnomaliesHysteresis flags sustained deviations using a rolling z-score
+// with two thresholds. Each value is scored against the mean and population
+// standard deviation of the non-null values in the window positions before
+// it, so a spike does not inflate its own baseline. A point enters the
+// anomalous state when its absolute z-score reaches enterThreshold and stays
+// there until the score falls below exitThreshold, which prevents flapping
+// around a single cutoff. The mask holds the state at each point.
+//
+// Zscore is null where the window has fewer than two values or no spread, and
+// the state carries over unchanged there. Null inputs yield nulls in both
+// arrays and also leave the state unchanged.
+func DetectAnomaliesHysteresis(ctx context.Context, col arrow.Array, window int, enterThreshold, exitThreshold float64) (*AnomalyResult, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("window must be at least 2, got %d", window)
+	}
+	if exitThreshold < 0 || !(exitThreshold <= enterThreshold) {
+		return nil, fmt.Errorf("thresholds must satisfy 0 <= exit <= enter, got enter %v and exit %v", enterThreshold, exitThreshold)
+	}
+
+	values, err := castFloat64(ctx, col)
+	if err != nil {
+		return nil, err
+	}
+	defer values.Release()
+
+	maskBuilder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer maskBuilder.Release()
+	zBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer zBuilder.Release()
+	maskBuilder.Reserve(values.Len())
+	zBuilder.Reserve(values.Len())
+
+	anomalous := false
+	for i := 0; i < values.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if values.IsNull(i) {
+			maskBuilder.AppendNull()
+			zBuilder.AppendNull()
+			continue
+		}
+
+		// Welford's mean and variance over the preceding window
+		var count int
+		var mean, m2 float64
+		for j := max(0, i-window); j < i; j++ {
+			if values.IsNull(j) {
+				continue
+			}
+			count++
+			delta := values.Value(j) - mean
+			mean += delta / float64(count)
+			m2 += delta * (values.Value(j) - mean)
+		}
+		std := math.Sqrt(m2 / float64(count))
+		if count < 2 || std == 0 {
+			maskBuilder.Append(anomalous)
+			zBuilder.AppendNull()
+			continue
+		}
+
+		z := (values.Value(i) - mean) / std
+		switch {
+		case !anomalous && math.Abs(z) >= enterThreshold:
+			anomalous = true
+		case anomalous && math.Abs(z) < exitThreshold:
+			anomalous = false
+		}
+		maskBuilder.Append(anomalous)
+		zBuilder.Append(z)
+	}
+
+	return &AnomalyResult{Mask: maskBuilder.NewBooleanArray(), Zscore: zBuilder.NewFloat64Array()}, nil
+}
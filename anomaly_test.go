@@ -52,3 +52,59 @@ func Example_detectAnomalies() {
 	// Z-scores:
 	// -1.4 -0.7 0.0 0.7 1.4
 }
+
+func Example_detectAnomaliesPercentile() {
+	// Skewed latencies with one slow outlier
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{12, 10, 11, 10, 13, 95}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	ctx := context.Background()
+	res, err := archery.DetectAnomaliesPercentile(ctx, arr, 0, 90)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer res.Release()
+
+	fmt.Println("Mask:", res.Mask)
+	fmt.Println("Percentile ranks:", res.Zscore)
+
+	// Output:
+	// Mask: [false false false false false true]
+	// Percentile ranks: [60 10 40 10 80 100]
+}
+
+func Example_detectAnomaliesHysteresis() {
+	// A metric that jumps to a sustained higher level, then recovers
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{10, 11, 10, 11, 10, 16, 17, 18, 19, 11, 10, 11, 10}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Enter the anomalous state at |z| >= 3 and leave it once |z| < 1
+	ctx := context.Background()
+	res, err := archery.DetectAnomaliesHysteresis(ctx, arr, 5, 3, 1)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer res.Release()
+	fmt.Println("Hysteresis:", res.Mask)
+
+	// A single cutoff flags only the jump itself
+	single, err := archery.DetectAnomaliesHysteresis(ctx, arr, 5, 3, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer single.Release()
+	fmt.Println("Single cutoff:", single.Mask)
+
+	// Output:
+	// Hysteresis: [false false false false false true true true true true true true false]
+	// Single cutoff: [false false false false false true false false false false false false false]
+}
@@ -3,11 +3,14 @@ package archery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
 // SortOrder specifies the order for sorting operations
@@ -34,6 +37,25 @@ func ReleaseRecord(rec arrow.Record) {
 	}
 }
 
+// ReleaseArrays safely releases each array, skipping nil entries
+func ReleaseArrays(arrs ...arrow.Array) {
+	for _, arr := range arrs {
+		ReleaseArray(arr)
+	}
+}
+
+// ReleaseRecords safely releases each record, skipping nil entries
+func ReleaseRecords(recs ...arrow.Record) {
+	for _, rec := range recs {
+		ReleaseRecord(rec)
+	}
+}
+
+// IsEmptyRecord reports whether a record is nil or has no rows
+func IsEmptyRecord(rec arrow.Record) bool {
+	return rec == nil || rec.NumRows() == 0
+}
+
 // ReplaceRecordColumn replaces a column in the record batch and returns a new record
 func ReplaceRecordColumn(rec arrow.Record, colIndex int, newCol arrow.Array) arrow.Record {
 	cols := make([]arrow.Array, rec.NumCols())
@@ -91,6 +113,40 @@ func GetColumn(rec arrow.Record, name string) (arrow.Array, error) {
 	return nil, fmt.Errorf("column not found: %s", name)
 }
 
+// RequireNonNull returns an error if any of the named columns contain nulls,
+// or any column at all when no names are given. The error names every
+// offending column with its null count and first null row.
+func RequireNonNull(rec arrow.Record, cols ...string) error {
+	if len(cols) == 0 {
+		cols = ColumnNames(rec)
+	}
+
+	var problems []string
+	for _, name := range cols {
+		idx, err := GetColumnIndex(rec, name)
+		if err != nil {
+			return err
+		}
+		col := rec.Column(idx)
+		nulls := CountNull(context.Background(), col)
+		if nulls == 0 {
+			continue
+		}
+
+		// Only scan for the first null once we know one exists
+		first := 0
+		for col.IsValid(first) {
+			first++
+		}
+		problems = append(problems, fmt.Sprintf("column %s has %d nulls (first at row %d)", name, nulls, first))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("null values found: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
 // GetColumnIndex returns the index of a column in a record batch by name
 func GetColumnIndex(rec arrow.Record, name string) (int, error) {
 	schema := rec.Schema()
@@ -102,6 +158,24 @@ func GetColumnIndex(rec arrow.Record, name string) (int, error) {
 	return -1, fmt.Errorf("column not found: %s", name)
 }
 
+// GetField returns the schema field of a column in a record batch by name
+func GetField(rec arrow.Record, name string) (arrow.Field, error) {
+	fields, ok := rec.Schema().FieldsByName(name)
+	if !ok {
+		return arrow.Field{}, fmt.Errorf("column not found: %s", name)
+	}
+	return fields[0], nil
+}
+
+// GetColumnType returns the data type of a column in a record batch by name
+func GetColumnType(rec arrow.Record, name string) (arrow.DataType, error) {
+	field, err := GetField(rec, name)
+	if err != nil {
+		return nil, err
+	}
+	return field.Type, nil
+}
+
 // ColumnNames returns the names of all columns in the record
 func ColumnNames(rec arrow.Record) []string {
 	schema := rec.Schema()
@@ -112,8 +186,302 @@ func ColumnNames(rec arrow.Record) []string {
 	return names
 }
 
+// SelectColumns returns a new record containing only the named columns, in the
+// order the names are given
+func SelectColumns(rec arrow.Record, names ...string) (arrow.Record, error) {
+	indices := make([]int, len(names))
+	for i, name := range names {
+		idx, err := GetColumnIndex(rec, name)
+		if err != nil {
+			return nil, err
+		}
+		indices[i] = idx
+	}
+	return selectColumnIndices(rec, indices), nil
+}
+
+// SelectColumnsInSchemaOrder returns a new record containing only the named
+// columns, in the order they appear in the record's schema regardless of the
+// order the names are given
+func SelectColumnsInSchemaOrder(rec arrow.Record, names ...string) (arrow.Record, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, err := GetColumnIndex(rec, name); err != nil {
+			return nil, err
+		}
+		wanted[name] = true
+	}
+
+	indices := make([]int, 0, len(wanted))
+	for i, field := range rec.Schema().Fields() {
+		if wanted[field.Name] {
+			indices = append(indices, i)
+		}
+	}
+	return selectColumnIndices(rec, indices), nil
+}
+
+// selectColumnIndices returns a new record with the columns at the given indices
+func selectColumnIndices(rec arrow.Record, indices []int) arrow.Record {
+	fields := make([]arrow.Field, len(indices))
+	cols := make([]arrow.Array, len(indices))
+	for i, idx := range indices {
+		fields[i] = rec.Schema().Field(idx)
+		cols[i] = rec.Column(idx)
+	}
+
+	metadata := rec.Schema().Metadata()
+	schema := arrow.NewSchema(fields, &metadata)
+	return array.NewRecord(schema, cols, rec.NumRows())
+}
+
+// DuplicateColumnPolicy controls what multi-record operations do when the
+// right-hand input has a column name that already exists on the left
+type DuplicateColumnPolicy int
+
+const (
+	// DuplicateError rejects the operation on any name collision
+	DuplicateError DuplicateColumnPolicy = iota
+	// DuplicateSuffixRight renames the right column by appending a suffix,
+	// "_right" by default
+	DuplicateSuffixRight
+	// DuplicateKeepLeft drops the right column and keeps the left one
+	DuplicateKeepLeft
+	// DuplicateSuffixBoth renames both columns by appending a suffix to each,
+	// "_left" and "_right" by default
+	DuplicateSuffixBoth
+)
+
+// Default suffixes for renamed duplicate columns
+const (
+	DefaultLeftSuffix  = "_left"
+	DefaultRightSuffix = "_right"
+)
+
+// AppendColumn returns a new record with col added as the last column under
+// field. A name that already exists is handled according to policy, which
+// defaults to DuplicateError; with DuplicateKeepLeft the input is returned
+// unchanged, with an extra reference the caller must release.
+func AppendColumn(rec arrow.Record, field arrow.Field, col arrow.Array, policy ...DuplicateColumnPolicy) (arrow.Record, error) {
+	if len(policy) > 1 {
+		return nil, fmt.Errorf("at most one policy may be given, got %d", len(policy))
+	}
+	if int64(col.Len()) != rec.NumRows() {
+		return nil, fmt.Errorf("column has %d rows, record has %d", col.Len(), rec.NumRows())
+	}
+	if !arrow.TypeEqual(field.Type, col.DataType()) {
+		return nil, fmt.Errorf("field type %s does not match column type %s", field.Type, col.DataType())
+	}
+	var p DuplicateColumnPolicy
+	if len(policy) == 1 {
+		p = policy[0]
+	}
+
+	fields, keep, err := mergeFields(rec.Schema(), arrow.NewSchema([]arrow.Field{field}, nil), p, DefaultLeftSuffix, DefaultRightSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if !keep[0] {
+		rec.Retain()
+		return rec, nil
+	}
+
+	cols := append(append([]arrow.Array{}, rec.Columns()...), col)
+	metadata := rec.Schema().Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &metadata), cols, rec.NumRows()), nil
+}
+
+// mergeFields returns the fields of left followed by those of right, resolving
+// name collisions by policy with the given suffixes. keep reports which right
+// fields are included.
+func mergeFields(left, right *arrow.Schema, policy DuplicateColumnPolicy, leftSuffix, rightSuffix string) (fields []arrow.Field, keep []bool, err error) {
+	switch policy {
+	case DuplicateError, DuplicateSuffixRight, DuplicateKeepLeft, DuplicateSuffixBoth:
+	default:
+		return nil, nil, fmt.Errorf("unknown duplicate column policy: %d", policy)
+	}
+
+	leftNames := make(map[string]bool, left.NumFields())
+	for _, field := range left.Fields() {
+		leftNames[field.Name] = true
+	}
+	rightNames := make(map[string]bool, right.NumFields())
+	for _, field := range right.Fields() {
+		rightNames[field.Name] = true
+	}
+
+	fields = make([]arrow.Field, 0, left.NumFields()+right.NumFields())
+	keep = make([]bool, right.NumFields())
+	for _, field := range left.Fields() {
+		if policy == DuplicateSuffixBoth && rightNames[field.Name] {
+			field.Name += leftSuffix
+		}
+		fields = append(fields, field)
+	}
+	for i, field := range right.Fields() {
+		if leftNames[field.Name] {
+			switch policy {
+			case DuplicateError:
+				return nil, nil, fmt.Errorf("duplicate column name: %s", field.Name)
+			case DuplicateKeepLeft:
+				continue
+			default:
+				field.Name += rightSuffix
+			}
+		}
+		fields = append(fields, field)
+		keep[i] = true
+	}
+
+	// Renaming can itself collide with an existing name
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if names[field.Name] {
+			return nil, nil, fmt.Errorf("duplicate column name: %s", field.Name)
+		}
+		names[field.Name] = true
+	}
+	return fields, keep, nil
+}
+
+// ConcatRecords stacks records with identical schemas into one record,
+// copying their rows in argument order. To allocate the result from another
+// allocator, pass the records to Compact under a context from WithAllocator.
+func ConcatRecords(recs ...arrow.Record) (arrow.Record, error) {
+	return concatRecords(memory.DefaultAllocator, recs)
+}
+
+// concatRecords is ConcatRecords allocating from mem
+func concatRecords(mem memory.Allocator, recs []arrow.Record) (arrow.Record, error) {
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("at least one record is required")
+	}
+
+	schema := recs[0].Schema()
+	var numRows int64
+	for i, rec := range recs {
+		if !rec.Schema().Equal(schema) {
+			return nil, fmt.Errorf("record %d schema does not match the first record", i)
+		}
+		numRows += rec.NumRows()
+	}
+
+	// Release the columns on every path, including an allocator panic; the
+	// record retains its own references
+	cols := make([]arrow.Array, schema.NumFields())
+	defer func() {
+		ReleaseArrays(cols...)
+	}()
+	for i := range cols {
+		parts := make([]arrow.Array, len(recs))
+		for j, rec := range recs {
+			parts[j] = rec.Column(i)
+		}
+		col, err := array.Concatenate(parts, mem)
+		if err != nil {
+			return nil, fmt.Errorf("error concatenating column %d: %w", i, err)
+		}
+		cols[i] = col
+	}
+
+	return array.NewRecord(schema, cols, numRows), nil
+}
+
+// ZipArrays builds a record from parallel slices of column names and arrays,
+// with one nullable field per array. The arrays must all have the same length
+// and the names must be unique. The record retains the arrays, so the caller
+// may release its own references afterwards.
+func ZipArrays(names []string, arrs []arrow.Array) (arrow.Record, error) {
+	if len(names) != len(arrs) {
+		return nil, fmt.Errorf("got %d names for %d arrays", len(names), len(arrs))
+	}
+
+	fields := make([]arrow.Field, len(arrs))
+	seen := make(map[string]bool, len(names))
+	var numRows int64
+	for i, arr := range arrs {
+		if arr == nil {
+			return nil, fmt.Errorf("array %s is nil", names[i])
+		}
+		if seen[names[i]] {
+			return nil, fmt.Errorf("duplicate column name: %s", names[i])
+		}
+		seen[names[i]] = true
+		if i == 0 {
+			numRows = int64(arr.Len())
+		} else if int64(arr.Len()) != numRows {
+			return nil, fmt.Errorf("array %s has length %d, expected %d", names[i], arr.Len(), numRows)
+		}
+		fields[i] = arrow.Field{Name: names[i], Type: arr.DataType(), Nullable: true}
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrs, numRows), nil
+}
+
+// UnzipRecord returns the column names and columns of the record, the inverse
+// of ZipArrays. Each column is retained so it outlives the record; the caller
+// must release them, e.g. with ReleaseArrays.
+func UnzipRecord(rec arrow.Record) (names []string, arrs []arrow.Array) {
+	names = ColumnNames(rec)
+	arrs = make([]arrow.Array, rec.NumCols())
+	for i, col := range rec.Columns() {
+		col.Retain()
+		arrs[i] = col
+	}
+	return names, arrs
+}
+
+// ValueAt returns the value at index i of the array as a Go value, or nil if the
+// value is null. Primitive and string types are returned as their native Go types;
+// other types use the array's JSON representation.
+func ValueAt(arr arrow.Array, i int) interface{} {
+	if arr.IsNull(i) {
+		return nil
+	}
+
+	switch a := arr.(type) {
+	case *array.Boolean:
+		return a.Value(i)
+	case *array.Int8:
+		return a.Value(i)
+	case *array.Int16:
+		return a.Value(i)
+	case *array.Int32:
+		return a.Value(i)
+	case *array.Int64:
+		return a.Value(i)
+	case *array.Uint8:
+		return a.Value(i)
+	case *array.Uint16:
+		return a.Value(i)
+	case *array.Uint32:
+		return a.Value(i)
+	case *array.Uint64:
+		return a.Value(i)
+	case *array.Float32:
+		return a.Value(i)
+	case *array.Float64:
+		return a.Value(i)
+	case *array.String:
+		return a.Value(i)
+	case *array.LargeString:
+		return a.Value(i)
+	case *array.Binary:
+		return a.Value(i)
+	default:
+		return arr.GetOneForMarshal(i)
+	}
+}
+
 // Internal utility functions
 
+// kernelUnavailable reports whether a compute error means the function or a
+// kernel for the argument types does not exist, as opposed to a failure such
+// as cancellation or invalid input that a manual fallback should not mask
+func kernelUnavailable(err error) bool {
+	return errors.Is(err, arrow.ErrKey) || errors.Is(err, arrow.ErrNotImplemented)
+}
+
 // callFunction is a helper to call Arrow compute functions
 func callFunction(ctx context.Context, funcName string, args ...arrow.Array) (arrow.Array, error) {
 	// Convert arrays to datums
@@ -139,3 +507,25 @@ func callFunction(ctx context.Context, funcName string, args ...arrow.Array) (ar
 	}
 	return nil, nil
 }
+
+// castFloat64 casts a numeric array to Float64. The caller is responsible for
+// releasing the result.
+func castFloat64(ctx context.Context, input arrow.Array) (*array.Float64, error) {
+	if arr, ok := input.(*array.Float64); ok {
+		arr.Retain()
+		return arr, nil
+	}
+
+	switch input.DataType().ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64, arrow.FLOAT32:
+	default:
+		return nil, fmt.Errorf("expected a numeric array, got %s", input.DataType())
+	}
+
+	result, err := compute.CastToType(ctx, input, arrow.PrimitiveTypes.Float64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast to float64: %w", err)
+	}
+	return result.(*array.Float64), nil
+}
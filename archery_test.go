@@ -0,0 +1,271 @@
+package archery_test
+
+import (
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_selectColumns() {
+	// Create a test record with three columns
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	cols := make([]arrow.Array, 3)
+	for i := range cols {
+		builder.AppendValues([]int64{int64(i)}, nil)
+		cols[i] = builder.NewArray()
+		defer cols[i].Release()
+	}
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "b", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "c", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, cols, 1)
+	defer rec.Release()
+
+	// Select columns in argument order
+	reordered, err := archery.SelectColumns(rec, "c", "a")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(reordered)
+
+	// Select columns in schema order
+	ordered, err := archery.SelectColumnsInSchemaOrder(rec, "c", "a")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(ordered)
+
+	// Print the resulting column names
+	fmt.Println("Argument order:", archery.ColumnNames(reordered))
+	fmt.Println("Schema order:", archery.ColumnNames(ordered))
+
+	// Output:
+	// Argument order: [c a]
+	// Schema order: [a c]
+}
+
+func Example_releaseArrays() {
+	// Track allocations so leaks are visible
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+
+	builder := array.NewFloat64Builder(mem)
+	defer builder.Release()
+
+	// Build several intermediate arrays
+	arrs := make([]arrow.Array, 3)
+	for i := range arrs {
+		builder.AppendValues([]float64{float64(i), float64(i + 1)}, nil)
+		arrs[i] = builder.NewArray()
+	}
+	fmt.Println("Allocated:", mem.CurrentAlloc() > 0)
+
+	// Release them all at once; nil entries are skipped
+	archery.ReleaseArrays(append(arrs, nil)...)
+	fmt.Println("Allocated after release:", mem.CurrentAlloc() > 0)
+
+	// Output:
+	// Allocated: true
+	// Allocated after release: false
+}
+
+func Example_appendColumn() {
+	// Create a record with a value column
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3}, nil)
+	values := builder.NewArray()
+	defer values.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "value", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{values}, 3)
+	defer rec.Release()
+
+	// Another column also named "value"
+	builder.AppendValues([]int64{10, 20, 30}, nil)
+	other := builder.NewArray()
+	defer other.Release()
+	field := arrow.Field{Name: "value", Type: arrow.PrimitiveTypes.Int64}
+
+	// Collisions are an error by default
+	_, err := archery.AppendColumn(rec, field, other)
+	fmt.Println("Error:", err)
+
+	// Suffix the new column instead
+	appended, err := archery.AppendColumn(rec, field, other, archery.DuplicateSuffixRight)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer appended.Release()
+	fmt.Println("Suffixed:", archery.ColumnNames(appended))
+
+	// Or keep the existing column
+	kept, err := archery.AppendColumn(rec, field, other, archery.DuplicateKeepLeft)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer kept.Release()
+	fmt.Println("Kept:", archery.ColumnNames(kept))
+
+	// Output:
+	// Error: duplicate column name: value
+	// Suffixed: [value value_right]
+	// Kept: [value]
+}
+
+func Example_requireNonNull() {
+	// Create a record with a gap in one column
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3, 4}, nil)
+	ids := builder.NewArray()
+	defer ids.Release()
+	builder.AppendValues([]int64{7, 0, 9, 0}, []bool{true, false, true, false})
+	scores := builder.NewArray()
+	defer scores.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "score", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids, scores}, 4)
+	defer rec.Release()
+
+	// Guard the join key, then the whole record
+	fmt.Println("id:", archery.RequireNonNull(rec, "id"))
+	fmt.Println("all:", archery.RequireNonNull(rec))
+
+	// Output:
+	// id: <nil>
+	// all: null values found: column score has 2 nulls (first at row 1)
+}
+
+func Example_zipArrays() {
+	// Create two parallel arrays
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{1, 2, 3}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"a", "b", "c"}, nil)
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	// Zip them into a record without writing the schema by hand
+	rec, err := archery.ZipArrays([]string{"id", "name"}, []arrow.Array{ids, names})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+	fmt.Println("Schema fields:", rec.Schema().Field(0).Type, rec.Schema().Field(1).Type)
+	fmt.Println("Rows:", rec.NumRows())
+
+	// Unzip it again; the columns stay valid after the record is released
+	colNames, cols := archery.UnzipRecord(rec)
+	defer archery.ReleaseArrays(cols...)
+	fmt.Println("Names:", colNames)
+	fmt.Println("Second column:", cols[1])
+
+	// Arrays of different lengths are rejected
+	short := array.NewSlice(ids, 0, 2)
+	defer short.Release()
+	_, err = archery.ZipArrays([]string{"id", "name"}, []arrow.Array{short, names})
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Schema fields: int64 utf8
+	// Rows: 3
+	// Names: [id name]
+	// Second column: ["a" "b" "c"]
+	// Error: array name has length 3, expected 2
+}
+
+func Example_getField() {
+	// Create a record with a nullable column
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1.5, 2.5}, nil)
+	prices := builder.NewArray()
+	defer prices.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{prices}, 2)
+	defer rec.Release()
+
+	// Look up a column's field and type by name
+	field, err := archery.GetField(rec, "price")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Field:", field.Name, field.Type, field.Nullable)
+
+	dt, err := archery.GetColumnType(rec, "price")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Type:", dt)
+
+	// Missing columns are errors
+	_, err = archery.GetField(rec, "volume")
+	fmt.Println("Missing field:", err)
+	_, err = archery.GetColumnType(rec, "volume")
+	fmt.Println("Missing type:", err)
+
+	// Output:
+	// Field: price float64 true
+	// Type: float64
+	// Missing field: column not found: volume
+	// Missing type: column not found: volume
+}
+
+func Example_isEmptyRecord() {
+	// A record with columns but no rows
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	empty := builder.NewArray()
+	defer empty.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	noRows := array.NewRecord(schema, []arrow.Array{empty}, 0)
+	defer noRows.Release()
+
+	// A record with no columns at all
+	noCols := array.NewRecord(arrow.NewSchema(nil, nil), nil, 0)
+	defer noCols.Release()
+
+	// A record with data
+	builder.AppendValues([]int64{1, 2}, nil)
+	ids := builder.NewArray()
+	defer ids.Release()
+	withRows := array.NewRecord(schema, []arrow.Array{ids}, 2)
+	defer withRows.Release()
+
+	fmt.Println("No rows:", archery.IsEmptyRecord(noRows))
+	fmt.Println("No columns:", archery.IsEmptyRecord(noCols))
+	fmt.Println("Nil:", archery.IsEmptyRecord(nil))
+	fmt.Println("With rows:", archery.IsEmptyRecord(withRows))
+
+	// Output:
+	// No rows: true
+	// No columns: true
+	// Nil: true
+	// With rows: false
+}
@@ -3,8 +3,10 @@ package archery
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/arrow-go/v18/arrow/scalar"
@@ -57,6 +59,120 @@ func Sign(ctx context.Context, a arrow.Array) (arrow.Array, error) {
 	return callFunction(ctx, "sign", a)
 }
 
+// ShareOfTotal returns each element as a fraction of the sum of all non-null
+// elements, as a Float64 array. Nulls are preserved.
+func ShareOfTotal(ctx context.Context, a arrow.Array) (arrow.Array, error) {
+	values, err := nonNullFloat64s(a)
+	if err != nil {
+		return nil, fmt.Errorf("share of total: %w", err)
+	}
+
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("share of total: total is zero")
+	}
+
+	floats, err := compute.CastToType(ctx, a, arrow.PrimitiveTypes.Float64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast to float64: %w", err)
+	}
+	defer floats.Release()
+
+	return DivideScalar(ctx, floats, total)
+}
+
+// Clip limits each element to the range [lower, upper], returning a Float64
+// array. Nulls are preserved. The bounds must not be NaN; an infinite bound
+// leaves that side open.
+func Clip(ctx context.Context, a arrow.Array, lower, upper float64) (arrow.Array, error) {
+	if math.IsNaN(lower) || math.IsNaN(upper) {
+		return nil, fmt.Errorf("clip bounds must not be NaN, got %v and %v", lower, upper)
+	}
+	if lower > upper {
+		return nil, fmt.Errorf("lower bound %v exceeds upper bound %v", lower, upper)
+	}
+
+	floats, err := castFloat64(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("clip: %w", err)
+	}
+	defer floats.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(floats.Len())
+	for i := 0; i < floats.Len(); i++ {
+		if floats.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(min(max(floats.Value(i), lower), upper))
+	}
+	return builder.NewArray(), nil
+}
+
+// Winsorize caps values below the lowerQ quantile and above the upperQ
+// quantile to those thresholds, preserving the number of elements. Like
+// Quantile, the quantiles are fractions satisfying 0 <= lowerQ < upperQ <= 1,
+// so 0.05 is the 5th percentile. The result is a Float64 array and nulls are
+// preserved.
+func Winsorize(ctx context.Context, a arrow.Array, lowerQ, upperQ float64) (arrow.Array, error) {
+	if !(lowerQ >= 0 && lowerQ < upperQ && upperQ <= 1) {
+		return nil, fmt.Errorf("winsorize requires 0 <= lower < upper <= 1, got %v and %v", lowerQ, upperQ)
+	}
+
+	lower, err := Quantile(ctx, a, lowerQ)
+	if err != nil {
+		return nil, fmt.Errorf("winsorize: %w", err)
+	}
+	upper, err := Quantile(ctx, a, upperQ)
+	if err != nil {
+		return nil, fmt.Errorf("winsorize: %w", err)
+	}
+
+	// Without non-null values the quantiles are NaN and there is nothing to cap
+	if math.IsNaN(lower) {
+		lower, upper = math.Inf(-1), math.Inf(1)
+	}
+	return Clip(ctx, a, lower, upper)
+}
+
+// RoundToMultiple rounds each element to the nearest multiple of a positive
+// step, such as 0.05 for prices, returning a Float64 array. Ties follow the
+// given mode, compute.RoundHalfToEven by default, and nulls are preserved.
+func RoundToMultiple(ctx context.Context, a arrow.Array, multiple float64, mode ...compute.RoundMode) (arrow.Array, error) {
+	if !(multiple > 0) || math.IsInf(multiple, 0) {
+		return nil, fmt.Errorf("multiple must be positive and finite, got %v", multiple)
+	}
+	if len(mode) > 1 {
+		return nil, fmt.Errorf("at most one rounding mode may be given, got %d", len(mode))
+	}
+	opts := compute.RoundToMultipleOptions{
+		Multiple: scalar.NewFloat64Scalar(multiple),
+		Mode:     compute.RoundHalfToEven,
+	}
+	if len(mode) == 1 {
+		opts.Mode = mode[0]
+	}
+
+	floats, err := castFloat64(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("round to multiple: %w", err)
+	}
+	defer floats.Release()
+
+	result, err := compute.CallFunction(ctx, "round_to_multiple", &opts, compute.NewDatum(floats))
+	if err != nil {
+		return nil, fmt.Errorf("round to multiple: %w", err)
+	}
+	defer result.Release()
+
+	return datumToArray(result), nil
+}
+
 // SCALAR OPERATIONS
 
 // AddScalar adds a scalar value to each element of an array
@@ -406,6 +522,55 @@ func DivideColumnScalar(ctx context.Context, rec arrow.Record, colName string, v
 	return newRecord, nil
 }
 
+// MapRecord applies a unary compute function such as "abs", "negate" or
+// "sqrt" to every numeric column of the record and returns a new record.
+// Non-numeric columns are passed through unchanged. Field names, nullability
+// and metadata are kept, while a column's type follows the function's result
+// type. Opts may be nil for functions without options. Functions that change
+// the number of rows, such as "unique", or return a scalar are an error.
+func MapRecord(ctx context.Context, rec arrow.Record, funcName string, opts compute.FunctionOptions) (arrow.Record, error) {
+	fields := rec.Schema().Fields()
+	cols := make([]arrow.Array, rec.NumCols())
+	for i, col := range rec.Columns() {
+		if !isNumericType(col.DataType()) {
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+
+		result, err := compute.CallFunction(ctx, funcName, opts, compute.NewDatum(col))
+		if err != nil {
+			// Clean up already created columns
+			for j := 0; j < i; j++ {
+				cols[j].Release()
+			}
+			return nil, fmt.Errorf("failed to call %s on column %s: %w", funcName, fields[i].Name, err)
+		}
+
+		// Only element-wise functions keep the record's shape
+		if result.Kind() != compute.KindArray || result.Len() != rec.NumRows() {
+			result.Release()
+			for j := 0; j < i; j++ {
+				cols[j].Release()
+			}
+			return nil, fmt.Errorf("%s on column %s did not return an array of %d rows", funcName, fields[i].Name, rec.NumRows())
+		}
+		cols[i] = datumToArray(result)
+		result.Release()
+		fields[i].Type = cols[i].DataType()
+	}
+
+	metadata := rec.Schema().Metadata()
+	result := array.NewRecord(arrow.NewSchema(fields, &metadata), cols, rec.NumRows())
+
+	// Release the columns (record takes ownership)
+	for _, col := range cols {
+		col.Release()
+	}
+
+	return result, nil
+}
+
 // toArrowScalar converts a Go value to an Arrow scalar of the specified type
 func toArrowScalar(value interface{}, dataType arrow.DataType) (scalar.Scalar, error) {
 	// Handle nil values
@@ -419,53 +584,39 @@ func toArrowScalar(value interface{}, dataType arrow.DataType) (scalar.Scalar, e
 		if val, ok := value.(bool); ok {
 			return scalar.NewBooleanScalar(val), nil
 		}
-	case arrow.INT8:
-		if val, ok := value.(int8); ok {
-			return scalar.NewInt8Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= -128 && val <= 127 {
-			return scalar.NewInt8Scalar(int8(val)), nil
-		}
-	case arrow.INT16:
-		if val, ok := value.(int16); ok {
-			return scalar.NewInt16Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= -32768 && val <= 32767 {
-			return scalar.NewInt16Scalar(int16(val)), nil
-		}
-	case arrow.INT32:
-		if val, ok := value.(int32); ok {
-			return scalar.NewInt32Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= -2147483648 && val <= 2147483647 {
-			return scalar.NewInt32Scalar(int32(val)), nil
-		}
-	case arrow.INT64:
-		if val, ok := value.(int64); ok {
-			return scalar.NewInt64Scalar(val), nil
-		} else if val, ok := value.(int); ok {
-			return scalar.NewInt64Scalar(int64(val)), nil
-		}
-	case arrow.UINT8:
-		if val, ok := value.(uint8); ok {
-			return scalar.NewUint8Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= 0 && val <= 255 {
-			return scalar.NewUint8Scalar(uint8(val)), nil
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		val, ok, err := signedValue(value, dataType)
+		if err != nil {
+			return nil, err
 		}
-	case arrow.UINT16:
-		if val, ok := value.(uint16); ok {
-			return scalar.NewUint16Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= 0 && val <= 65535 {
-			return scalar.NewUint16Scalar(uint16(val)), nil
+		if ok {
+			switch dataType.ID() {
+			case arrow.INT8:
+				return scalar.NewInt8Scalar(int8(val)), nil
+			case arrow.INT16:
+				return scalar.NewInt16Scalar(int16(val)), nil
+			case arrow.INT32:
+				return scalar.NewInt32Scalar(int32(val)), nil
+			default:
+				return scalar.NewInt64Scalar(val), nil
+			}
 		}
-	case arrow.UINT32:
-		if val, ok := value.(uint32); ok {
-			return scalar.NewUint32Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= 0 && val <= 4294967295 {
-			return scalar.NewUint32Scalar(uint32(val)), nil
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		val, ok, err := unsignedValue(value, dataType)
+		if err != nil {
+			return nil, err
 		}
-	case arrow.UINT64:
-		if val, ok := value.(uint64); ok {
-			return scalar.NewUint64Scalar(val), nil
-		} else if val, ok := value.(int); ok && val >= 0 {
-			return scalar.NewUint64Scalar(uint64(val)), nil
+		if ok {
+			switch dataType.ID() {
+			case arrow.UINT8:
+				return scalar.NewUint8Scalar(uint8(val)), nil
+			case arrow.UINT16:
+				return scalar.NewUint16Scalar(uint16(val)), nil
+			case arrow.UINT32:
+				return scalar.NewUint32Scalar(uint32(val)), nil
+			default:
+				return scalar.NewUint64Scalar(val), nil
+			}
 		}
 	case arrow.FLOAT32:
 		if val, ok := value.(float32); ok {
@@ -501,3 +652,95 @@ func toArrowScalar(value interface{}, dataType arrow.DataType) (scalar.Scalar, e
 
 	return nil, fmt.Errorf("cannot convert %T to Arrow scalar of type %s", value, dataType)
 }
+
+// signedValue converts a Go integer or integral float to an int64 that fits the
+// signed integer type. It reports false if the value is not a number, and an
+// error if the conversion would lose information.
+func signedValue(value interface{}, dataType arrow.DataType) (int64, bool, error) {
+	var val int64
+	switch v := value.(type) {
+	case int:
+		val = int64(v)
+	case int8:
+		val = int64(v)
+	case int16:
+		val = int64(v)
+	case int32:
+		val = int64(v)
+	case int64:
+		val = v
+	case uint, uint8, uint16, uint32, uint64:
+		u, _, err := unsignedValue(v, arrow.PrimitiveTypes.Uint64)
+		if err != nil {
+			return 0, false, err
+		}
+		if u > math.MaxInt64 {
+			return 0, false, fmt.Errorf("value %v out of range for %s", value, dataType)
+		}
+		val = int64(u)
+	case float32:
+		return signedValue(float64(v), dataType)
+	case float64:
+		if v != math.Trunc(v) || math.IsInf(v, 0) {
+			return 0, false, fmt.Errorf("cannot convert %v to %s without losing precision", v, dataType)
+		}
+		if v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false, fmt.Errorf("value %v out of range for %s", v, dataType)
+		}
+		val = int64(v)
+	default:
+		return 0, false, nil
+	}
+
+	bits := dataType.(arrow.FixedWidthDataType).BitWidth()
+	if bits < 64 && (val < -1<<(bits-1) || val > 1<<(bits-1)-1) {
+		return 0, false, fmt.Errorf("value %v out of range for %s", value, dataType)
+	}
+	return val, true, nil
+}
+
+// unsignedValue converts a Go integer or integral float to a uint64 that fits
+// the unsigned integer type. It reports false if the value is not a number, and
+// an error if the conversion would lose information.
+func unsignedValue(value interface{}, dataType arrow.DataType) (uint64, bool, error) {
+	var val uint64
+	switch v := value.(type) {
+	case uint:
+		val = uint64(v)
+	case uint8:
+		val = uint64(v)
+	case uint16:
+		val = uint64(v)
+	case uint32:
+		val = uint64(v)
+	case uint64:
+		val = v
+	case int, int8, int16, int32, int64:
+		i, _, err := signedValue(v, arrow.PrimitiveTypes.Int64)
+		if err != nil {
+			return 0, false, err
+		}
+		if i < 0 {
+			return 0, false, fmt.Errorf("value %v out of range for %s", value, dataType)
+		}
+		val = uint64(i)
+	case float32:
+		return unsignedValue(float64(v), dataType)
+	case float64:
+		if v != math.Trunc(v) || math.IsInf(v, 0) {
+			return 0, false, fmt.Errorf("cannot convert %v to %s without losing precision", v, dataType)
+		}
+		if v < 0 || v >= math.MaxUint64 {
+			return 0, false, fmt.Errorf("value %v out of range for %s", v, dataType)
+		}
+		val = uint64(v)
+	default:
+		return 0, false, nil
+	}
+
+	bits := dataType.(arrow.FixedWidthDataType).BitWidth()
+	if bits < 64 && val > 1<<bits-1 {
+		return 0, false, fmt.Errorf("value %v out of range for %s", value, dataType)
+	}
+	return val, true, nil
+}
@@ -3,9 +3,12 @@ package archery_test
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
@@ -175,3 +178,228 @@ func Example_abs() {
 	// Absolute values:
 	// 1.0 2.0 3.0 4.0 5.0
 }
+
+func Example_shareOfTotal() {
+	// Create a test array of revenue per category
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{50, 30, 20}, nil)
+	arr := builder.NewInt64Array()
+	defer arr.Release()
+
+	// Calculate each category's share of the total
+	ctx := context.Background()
+	shares, err := archery.ShareOfTotal(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(shares)
+
+	// Print the result
+	fmt.Println(shares)
+
+	// Output:
+	// [0.5 0.3 0.2]
+}
+
+func Example_scalarConversion() {
+	// Create an integer array
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3, 4}, nil)
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Integral floats are accepted for integer columns
+	ctx := context.Background()
+	mask, err := archery.GreaterScalar(ctx, arr, 2.0)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(mask)
+	fmt.Println("Greater than 2.0:", mask)
+
+	// Fractional floats would be truncated, so they are rejected
+	_, err = archery.GreaterScalar(ctx, arr, 2.5)
+	fmt.Println("Greater than 2.5:", err)
+
+	// Output:
+	// Greater than 2.0: [false false true true]
+	// Greater than 2.5: failed to convert scalar: cannot convert 2.5 to int64 without losing precision
+}
+
+func Example_winsorize() {
+	// Create a test array with outliers at both ends
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{-50, 1, 2, 3, 4, 5, 6, 7, 8, 9, 200}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Cap values at the 10th and 90th percentiles
+	ctx := context.Background()
+	capped, err := archery.Winsorize(ctx, arr, 0.1, 0.9)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(capped)
+
+	// Print the result
+	fmt.Println(capped)
+
+	// An array of nulls has nothing to cap
+	builder.AppendNulls(2)
+	nulls := builder.NewFloat64Array()
+	defer nulls.Release()
+	unchanged, err := archery.Winsorize(ctx, nulls, 0.1, 0.9)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(unchanged)
+	fmt.Println(unchanged)
+
+	// Output:
+	// [1 1 2 3 4 5 6 7 8 9 9]
+	// [(null) (null)]
+}
+
+func Example_mapRecord() {
+	// Create a record with measures and a label
+	labelBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer labelBuilder.Release()
+	labelBuilder.AppendValues([]string{"a", "b", "c"}, nil)
+	labels := labelBuilder.NewArray()
+	defer labels.Release()
+
+	deltaBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer deltaBuilder.Release()
+	deltaBuilder.AppendValues([]int64{-3, 5, -1}, nil)
+	deltas := deltaBuilder.NewArray()
+	defer deltas.Release()
+
+	errBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer errBuilder.Release()
+	errBuilder.AppendValues([]float64{0.5, -2.25, 0}, nil)
+	errs := errBuilder.NewArray()
+	defer errs.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "label", Type: arrow.BinaryTypes.String},
+		{Name: "delta", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "error", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{labels, deltas, errs}, 3)
+	defer rec.Release()
+
+	// Take the absolute value of every measure column
+	ctx := context.Background()
+	result, err := archery.MapRecord(ctx, rec, "abs", nil)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(result)
+
+	// Print the columns
+	for i, col := range result.Columns() {
+		fmt.Println(result.ColumnName(i), col)
+	}
+
+	// Output:
+	// label ["a" "b" "c"]
+	// delta [3 5 1]
+	// error [0.5 2.25 0]
+}
+
+func Example_mapRecordLengthChange() {
+	// Create a record with a repeated value
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 1, 2}, nil)
+	values := builder.NewArray()
+	defer values.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "value", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{values}, 3)
+	defer rec.Release()
+
+	// unique is unary but drops rows, so it cannot map a record
+	ctx := context.Background()
+	_, err := archery.MapRecord(ctx, rec, "unique", nil)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Error: unique on column value did not return an array of 3 rows
+}
+
+func Example_roundToMultiple() {
+	// Create a test array with a null
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{0.125, 0.375, 1.1, 0}, []bool{true, true, true, false})
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Snap to a grid of quarters, with ties to even and ties up
+	ctx := context.Background()
+	even, err := archery.RoundToMultiple(ctx, arr, 0.25)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer even.Release()
+
+	up, err := archery.RoundToMultiple(ctx, arr, 0.25, compute.RoundHalfUp)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer up.Release()
+
+	fmt.Println("Half to even:", even)
+	fmt.Println("Half up:", up)
+
+	// Output:
+	// Half to even: [0 0.5 1 (null)]
+	// Half up: [0.25 0.5 1 (null)]
+}
+
+func Example_clip() {
+	// Create a test array with a null
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{-5, 3, 0, 12}, []bool{true, true, false, true})
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Limit the values to [0, 10]
+	ctx := context.Background()
+	clipped, err := archery.Clip(ctx, arr, 0, 10)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(clipped)
+	fmt.Println(clipped)
+
+	// An infinite bound leaves that side open, but NaN bounds are rejected
+	floored, err := archery.Clip(ctx, arr, 0, math.Inf(1))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(floored)
+	fmt.Println(floored)
+
+	_, err = archery.Clip(ctx, arr, math.NaN(), 10)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// [0 3 (null) 10]
+	// [0 3 (null) 12]
+	// Error: clip bounds must not be NaN, got NaN and 10
+}
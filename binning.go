@@ -0,0 +1,157 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// BINNING OPERATIONS
+
+// QCutOptions controls how QCut handles ties between quantile edges
+type QCutOptions struct {
+	// DropDuplicates merges buckets whose edges coincide, which happens when
+	// many values tie. When false, duplicate edges are an error.
+	DropDuplicates bool
+}
+
+// QCut assigns each value to one of q equal-frequency buckets by quantile and
+// returns an Int64 array of bucket indices in [0, q). Bucket i holds the values
+// in (edge i, edge i+1], with the lowest value placed in bucket 0. Nulls map
+// to null.
+func QCut(ctx context.Context, input arrow.Array, q int, opts ...QCutOptions) (arrow.Array, error) {
+	if q < 1 {
+		return nil, fmt.Errorf("number of buckets must be positive, got %d", q)
+	}
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	var options QCutOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+
+	values, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("qcut: %w", err)
+	}
+	defer values.Release()
+
+	edges, err := quantileEdges(values, q, options.DropDuplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(values.Len())
+
+	upper := edges[1:]
+	for i := 0; i < values.Len(); i++ {
+		if values.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		// Find the first upper edge not below the value
+		bucket := sort.SearchFloat64s(upper, values.Value(i))
+		if bucket >= len(upper) && bucket > 0 {
+			bucket = len(upper) - 1
+		}
+		builder.Append(int64(bucket))
+	}
+
+	return builder.NewArray(), nil
+}
+
+// Histogram counts the values of a numeric array in bins equal-width bins
+// spanning the minimum to the maximum value. It returns bins+1 edges and one
+// count per bin; bin i holds values in [edge i, edge i+1), except the last bin,
+// which also includes the maximum. Nulls, NaNs and infinities are ignored. If
+// all values are equal, the range is widened to half a unit either side.
+func Histogram(ctx context.Context, input arrow.Array, bins int) (edges []float64, counts []int64, err error) {
+	if bins < 1 {
+		return nil, nil, fmt.Errorf("number of bins must be positive, got %d", bins)
+	}
+
+	values, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("histogram: %w", err)
+	}
+	defer values.Release()
+
+	// finite reports whether element i takes part in the histogram
+	finite := func(i int) bool {
+		v := values.Value(i)
+		return values.IsValid(i) && !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i := 0; i < values.Len(); i++ {
+		if finite(i) {
+			lo = min(lo, values.Value(i))
+			hi = max(hi, values.Value(i))
+		}
+	}
+	if lo > hi {
+		return nil, nil, fmt.Errorf("histogram: no finite non-null values")
+	}
+	if lo == hi {
+		lo, hi = lo-0.5, hi+0.5
+		if lo == hi {
+			// Half a unit is below the precision of large magnitudes
+			lo, hi = math.Nextafter(lo, math.Inf(-1)), math.Nextafter(hi, math.Inf(1))
+		}
+	}
+
+	// Work with half the span so that hi-lo cannot overflow near ±MaxFloat64
+	halfSpan := hi/2 - lo/2
+	edges = make([]float64, bins+1)
+	for i := range edges {
+		t := float64(i) / float64(bins)
+		edges[i] = lo*(1-t) + hi*t
+	}
+	edges[0], edges[bins] = lo, hi
+
+	counts = make([]int64, bins)
+	for i := 0; i < values.Len(); i++ {
+		if !finite(i) {
+			continue
+		}
+		frac := (values.Value(i)/2 - lo/2) / halfSpan
+		bin := int(frac * float64(bins))
+		counts[max(0, min(bin, bins-1))]++
+	}
+	return edges, counts, nil
+}
+
+// quantileEdges returns the q+1 quantile edges of the non-null values. With
+// dropDuplicates, fewer edges are returned when values tie, down to a single
+// edge when all values are equal.
+func quantileEdges(values *array.Float64, q int, dropDuplicates bool) ([]float64, error) {
+	sorted := appendNonNull(make([]float64, 0, values.Len()-values.NullN()), values)
+	if len(sorted) == 0 {
+		return []float64{0}, nil
+	}
+	slices.Sort(sorted)
+
+	edges := make([]float64, q+1)
+	for i := range edges {
+		edges[i] = quantileSorted(sorted, float64(i)/float64(q))
+	}
+
+	for i := 1; i < len(edges); i++ {
+		if edges[i] == edges[i-1] {
+			if !dropDuplicates {
+				return nil, fmt.Errorf("duplicate bucket edge %v; set DropDuplicates to merge buckets", edges[i])
+			}
+			return slices.Compact(edges), nil
+		}
+	}
+	return edges, nil
+}
@@ -0,0 +1,124 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_qCut() {
+	// Create a test array with a null
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{7, 1, 4, 0, 8, 2, 5, 3, 6}, []bool{true, true, true, false, true, true, true, true, true})
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Split into quartiles
+	ctx := context.Background()
+	buckets, err := archery.QCut(ctx, arr, 4)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(buckets)
+
+	// Heavily tied values produce duplicate edges
+	builder.AppendValues([]float64{1, 1, 1, 1, 1, 2, 3, 4}, nil)
+	tied := builder.NewFloat64Array()
+	defer tied.Release()
+
+	_, err = archery.QCut(ctx, tied, 4)
+	fmt.Println("Error:", err)
+
+	merged, err := archery.QCut(ctx, tied, 4, archery.QCutOptions{DropDuplicates: true})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(merged)
+
+	// Print the results
+	fmt.Println("Quartiles:", buckets)
+	fmt.Println("Merged:", merged)
+
+	// Output:
+	// Error: duplicate bucket edge 1; set DropDuplicates to merge buckets
+	// Quartiles: [3 0 1 (null) 3 0 2 1 2]
+	// Merged: [0 0 0 0 0 0 1 1]
+}
+
+func Example_histogram() {
+	// Create a test array
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{0, 1, 1.5, 2, 3, 4}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Count values in four equal-width bins
+	ctx := context.Background()
+	edges, counts, err := archery.Histogram(ctx, arr, 4)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println("Edges:", edges)
+	fmt.Println("Counts:", counts)
+
+	// Output:
+	// Edges: [0 1 2 3 4]
+	// Counts: [1 2 1 2]
+}
+
+func Example_histogramExtremes() {
+	ctx := context.Background()
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+
+	// Values spanning almost the whole float64 range
+	builder.AppendValues([]float64{-math.MaxFloat64, -1e308, 0, 1e308, math.MaxFloat64}, nil)
+	wide := builder.NewFloat64Array()
+	defer wide.Release()
+
+	edges, counts, err := archery.Histogram(ctx, wide, 2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Wide edges:", edges)
+	fmt.Println("Wide counts:", counts)
+
+	// Infinities are ignored like NaN
+	builder.AppendValues([]float64{math.Inf(-1), 1, 2, math.NaN(), 3, math.Inf(1)}, nil)
+	withInf := builder.NewFloat64Array()
+	defer withInf.Release()
+
+	edges, counts, err = archery.Histogram(ctx, withInf, 2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Finite edges:", edges)
+	fmt.Println("Finite counts:", counts)
+
+	// Infinities alone leave nothing to count
+	builder.AppendValues([]float64{math.Inf(1), math.Inf(-1)}, nil)
+	onlyInf := builder.NewFloat64Array()
+	defer onlyInf.Release()
+
+	_, _, err = archery.Histogram(ctx, onlyInf, 2)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Wide edges: [-1.7976931348623157e+308 0 1.7976931348623157e+308]
+	// Wide counts: [2 3]
+	// Finite edges: [1 2 3]
+	// Finite counts: [1 2]
+	// Error: histogram: no finite non-null values
+}
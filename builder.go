@@ -0,0 +1,222 @@
+package archery
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// RECORD BUILDING
+
+// RecordBuilder builds a record row by row from Go values. Each appended value
+// is checked against the schema before anything is written, so a rejected row
+// leaves the builder unchanged.
+type RecordBuilder struct {
+	schema   *arrow.Schema
+	builders []array.Builder
+	rows     int64
+}
+
+// NewRecordBuilder returns a RecordBuilder for the schema. Supported column
+// types are boolean, the integer and floating point types, string and binary.
+// The caller is responsible for calling Release.
+func NewRecordBuilder(schema *arrow.Schema) (*RecordBuilder, error) {
+	builders := make([]array.Builder, len(schema.Fields()))
+	for i, field := range schema.Fields() {
+		switch field.Type.ID() {
+		case arrow.BOOL,
+			arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+			arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+			arrow.FLOAT32, arrow.FLOAT64, arrow.STRING, arrow.BINARY:
+		default:
+			for _, b := range builders[:i] {
+				b.Release()
+			}
+			return nil, fmt.Errorf("unsupported type %s for column %s", field.Type, field.Name)
+		}
+		builders[i] = array.NewBuilder(memory.DefaultAllocator, field.Type)
+	}
+	return &RecordBuilder{schema: schema, builders: builders}, nil
+}
+
+// AppendRow appends one row with a value per column in schema order. A nil
+// value appends a null and is only allowed for nullable fields. Integer
+// columns accept any Go integer or an integral float that fits the type.
+func (b *RecordBuilder) AppendRow(values ...interface{}) error {
+	fields := b.schema.Fields()
+	if len(values) != len(fields) {
+		return fmt.Errorf("expected %d values, got %d", len(fields), len(values))
+	}
+
+	// Convert every value before appending so a bad row is not half written
+	converted := make([]interface{}, len(values))
+	for i, field := range fields {
+		val, err := rowValue(values[i], field)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		converted[i] = val
+	}
+
+	for i, val := range converted {
+		appendRowValue(b.builders[i], val)
+	}
+	b.rows++
+	return nil
+}
+
+// NumRows returns the number of rows appended since the last Build
+func (b *RecordBuilder) NumRows() int64 {
+	return b.rows
+}
+
+// Build returns a record of the rows appended so far and resets the builder.
+// The caller is responsible for releasing the record.
+func (b *RecordBuilder) Build() arrow.Record {
+	cols := make([]arrow.Array, len(b.builders))
+	for i, builder := range b.builders {
+		cols[i] = builder.NewArray()
+	}
+
+	rec := array.NewRecord(b.schema, cols, b.rows)
+	for _, col := range cols {
+		col.Release()
+	}
+	b.rows = 0
+	return rec
+}
+
+// Release releases the column builders
+func (b *RecordBuilder) Release() {
+	for _, builder := range b.builders {
+		builder.Release()
+	}
+}
+
+// rowValue converts a Go value to the native type appended for the field
+func rowValue(value interface{}, field arrow.Field) (interface{}, error) {
+	if value == nil {
+		if !field.Nullable {
+			return nil, fmt.Errorf("null value for non-nullable field")
+		}
+		return nil, nil
+	}
+
+	switch field.Type.ID() {
+	case arrow.BOOL:
+		if val, ok := value.(bool); ok {
+			return val, nil
+		}
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		val, ok, err := signedValue(value, field.Type)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return val, nil
+		}
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		val, ok, err := unsignedValue(value, field.Type)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return val, nil
+		}
+	case arrow.FLOAT32, arrow.FLOAT64:
+		val, ok := floatValue(value)
+		if ok && field.Type.ID() == arrow.FLOAT32 && math.Abs(val) > math.MaxFloat32 && !math.IsInf(val, 0) {
+			return nil, fmt.Errorf("value %v out of range for %s", value, field.Type)
+		}
+		if ok {
+			return val, nil
+		}
+	case arrow.STRING:
+		switch val := value.(type) {
+		case string:
+			return val, nil
+		case []byte:
+			return string(val), nil
+		}
+	case arrow.BINARY:
+		switch val := value.(type) {
+		case []byte:
+			return val, nil
+		case string:
+			return []byte(val), nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot append %T to column of type %s", value, field.Type)
+}
+
+// floatValue converts a Go float or integer to float64
+func floatValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// appendRowValue appends a value converted by rowValue to the builder
+func appendRowValue(builder array.Builder, val interface{}) {
+	if val == nil {
+		builder.AppendNull()
+		return
+	}
+
+	switch b := builder.(type) {
+	case *array.BooleanBuilder:
+		b.Append(val.(bool))
+	case *array.Int8Builder:
+		b.Append(int8(val.(int64)))
+	case *array.Int16Builder:
+		b.Append(int16(val.(int64)))
+	case *array.Int32Builder:
+		b.Append(int32(val.(int64)))
+	case *array.Int64Builder:
+		b.Append(val.(int64))
+	case *array.Uint8Builder:
+		b.Append(uint8(val.(uint64)))
+	case *array.Uint16Builder:
+		b.Append(uint16(val.(uint64)))
+	case *array.Uint32Builder:
+		b.Append(uint32(val.(uint64)))
+	case *array.Uint64Builder:
+		b.Append(val.(uint64))
+	case *array.Float32Builder:
+		b.Append(float32(val.(float64)))
+	case *array.Float64Builder:
+		b.Append(val.(float64))
+	case *array.StringBuilder:
+		b.Append(val.(string))
+	case *array.BinaryBuilder:
+		b.Append(val.([]byte))
+	}
+}
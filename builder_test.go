@@ -0,0 +1,55 @@
+package archery_test
+
+import (
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func Example_recordBuilder() {
+	// Describe the record to build
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "age", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	builder, err := archery.NewRecordBuilder(schema)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer builder.Release()
+
+	// Append rows one at a time
+	if err := builder.AppendRow("alice", 30, 9.5); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if err := builder.AppendRow("bob", 25, nil); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Mismatched values are rejected without changing the builder
+	err = builder.AppendRow("carol", "forty", 7.0)
+	fmt.Println("Rejected:", err)
+
+	rec := builder.Build()
+	defer rec.Release()
+
+	// Print the record
+	table, err := archery.FormatRecord(rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(table)
+
+	// Output:
+	// Rejected: column age: cannot append string to column of type int32
+	// name   age  score
+	// alice  30   9.5
+	// bob    25   null
+}
@@ -0,0 +1,213 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// CHUNKED OPERATIONS
+
+// RecordToChunked returns each column of the record as a single-chunk chunked array.
+// The caller is responsible for releasing the returned chunked arrays.
+func RecordToChunked(rec arrow.Record) []*arrow.Chunked {
+	chunks := make([]*arrow.Chunked, rec.NumCols())
+	for i, col := range rec.Columns() {
+		chunks[i] = arrow.NewChunked(col.DataType(), []arrow.Array{col})
+	}
+	return chunks
+}
+
+// ColumnChunked returns a column of the record by name as a single-chunk chunked array.
+// The caller is responsible for releasing the returned chunked array.
+func ColumnChunked(rec arrow.Record, name string) (*arrow.Chunked, error) {
+	col, err := GetColumn(rec, name)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(col)
+
+	return arrow.NewChunked(col.DataType(), []arrow.Array{col}), nil
+}
+
+// ChunkedToArray concatenates the chunks of a chunked array into one contiguous array
+func ChunkedToArray(chunked *arrow.Chunked) (arrow.Array, error) {
+	chunks := chunked.Chunks()
+	if len(chunks) == 0 {
+		return array.MakeArrayOfNull(memory.DefaultAllocator, chunked.DataType(), 0), nil
+	}
+	if len(chunks) == 1 {
+		chunks[0].Retain()
+		return chunks[0], nil
+	}
+
+	result, err := array.Concatenate(chunks, memory.DefaultAllocator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to concatenate chunks: %w", err)
+	}
+	return result, nil
+}
+
+// ChunkedToRecord builds a record from one chunked array per schema field,
+// concatenating the chunks of each column. Every column must have its field's
+// type and the same number of rows.
+func ChunkedToRecord(schema *arrow.Schema, columns []*arrow.Chunked) (arrow.Record, error) {
+	if len(columns) != schema.NumFields() {
+		return nil, fmt.Errorf("number of columns (%d) does not match number of fields (%d)",
+			len(columns), schema.NumFields())
+	}
+
+	// Check shapes up front so that nothing is concatenated for bad input
+	for i, chunked := range columns {
+		field := schema.Field(i)
+		if !arrow.TypeEqual(chunked.DataType(), field.Type) {
+			return nil, fmt.Errorf("column %s has type %s, schema expects %s", field.Name, chunked.DataType(), field.Type)
+		}
+		if chunked.Len() != columns[0].Len() {
+			return nil, fmt.Errorf("column %s has %d rows, expected %d", field.Name, chunked.Len(), columns[0].Len())
+		}
+	}
+
+	cols := make([]arrow.Array, len(columns))
+	for i, chunked := range columns {
+		col, err := ChunkedToArray(chunked)
+		if err != nil {
+			// Clean up already created columns
+			for j := 0; j < i; j++ {
+				cols[j].Release()
+			}
+			return nil, fmt.Errorf("error converting column %d: %w", i, err)
+		}
+		cols[i] = col
+	}
+
+	var numRows int64
+	if len(cols) > 0 {
+		numRows = int64(cols[0].Len())
+	}
+	result := array.NewRecord(schema, cols, numRows)
+
+	// Release the columns (record takes ownership)
+	for _, col := range cols {
+		col.Release()
+	}
+
+	return result, nil
+}
+
+// NewTable returns a single-chunk table backed by the record's columns.
+// The caller is responsible for releasing the returned table.
+func NewTable(rec arrow.Record) arrow.Table {
+	return array.NewTableFromRecords(rec.Schema(), []arrow.Record{rec})
+}
+
+// TableToRecord materializes a table into one contiguous record, concatenating
+// the chunks of every column. This copies the data of multi-chunk columns, so
+// the record needs as much memory again as the table.
+func TableToRecord(tbl arrow.Table) (arrow.Record, error) {
+	columns := make([]*arrow.Chunked, tbl.NumCols())
+	for i := range columns {
+		columns[i] = tbl.Column(i).Data()
+	}
+	return ChunkedToRecord(tbl.Schema(), columns)
+}
+
+// Compact coalesces consecutive records into fewer, larger records of at least
+// targetRows rows each, except possibly the last. Records are never split, so a
+// record already holding targetRows rows or more is passed through as is, and a
+// batch can overshoot the target by up to one record. Empty records are dropped.
+// All records must share the first record's schema. Under a context from
+// WithAllocator the merged records are allocated from that allocator. The
+// caller is responsible for releasing the returned records.
+func Compact(ctx context.Context, recs []arrow.Record, targetRows int64) ([]arrow.Record, error) {
+	if targetRows < 1 {
+		return nil, fmt.Errorf("target rows must be positive, got %d", targetRows)
+	}
+
+	// Release the merged records on every failure, including an allocator panic
+	var result []arrow.Record
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			ReleaseRecords(result...)
+		}
+	}()
+	var pending []arrow.Record
+	var pendingRows int64
+
+	flush := func() error {
+		defer func() {
+			pending = pending[:0]
+			pendingRows = 0
+		}()
+		if len(pending) == 1 {
+			// A lone record needs no copy
+			pending[0].Retain()
+			result = append(result, pending[0])
+			return nil
+		}
+		mem, _ := contextAllocator(ctx)
+		merged, err := concatRecords(mem, pending)
+		if err != nil {
+			return err
+		}
+		result = append(result, merged)
+		return nil
+	}
+
+	for i, rec := range recs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !rec.Schema().Equal(recs[0].Schema()) {
+			return nil, fmt.Errorf("record %d schema does not match the first record", i)
+		}
+		if rec.NumRows() == 0 {
+			continue
+		}
+
+		pending = append(pending, rec)
+		pendingRows += rec.NumRows()
+		if pendingRows >= targetRows {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	succeeded = true
+	return result, nil
+}
+
+// ChunkRecord splits a record into consecutive zero-copy slices of chunkSize
+// rows, the last of which may be shorter, e.g. to hand each chunk to a worker
+// goroutine. It is the inverse of Compact; an empty record yields no chunks.
+// The chunks share the input's buffers and the caller is responsible for
+// releasing them.
+func ChunkRecord(rec arrow.Record, chunkSize int64) ([]arrow.Record, error) {
+	if chunkSize < 1 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+
+	// Neither the chunk count nor the slice ends may overflow for huge chunk sizes
+	numRows := rec.NumRows()
+	numChunks := numRows / chunkSize
+	if numRows%chunkSize != 0 {
+		numChunks++
+	}
+	chunks := make([]arrow.Record, 0, numChunks)
+	for start := int64(0); start < numRows; {
+		end := start + min(chunkSize, numRows-start)
+		chunks = append(chunks, rec.NewSlice(start, end))
+		start = end
+	}
+	return chunks, nil
+}
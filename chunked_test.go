@@ -0,0 +1,206 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_chunkedToRecord() {
+	// Create a chunked column from two arrays
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2}, nil)
+	first := builder.NewArray()
+	defer first.Release()
+	builder.AppendValues([]int64{3, 4, 5}, nil)
+	second := builder.NewArray()
+	defer second.Release()
+
+	chunked := arrow.NewChunked(arrow.PrimitiveTypes.Int64, []arrow.Array{first, second})
+	defer chunked.Release()
+
+	// Materialize the chunks into a single record
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec, err := archery.ChunkedToRecord(schema, []*arrow.Chunked{chunked})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(rec)
+
+	// Convert back to a chunked view
+	columns := archery.RecordToChunked(rec)
+	defer func() {
+		for _, col := range columns {
+			col.Release()
+		}
+	}()
+
+	// Print the results
+	fmt.Println("Rows:", rec.NumRows())
+	fmt.Println("Values:", rec.Column(0))
+	fmt.Println("Chunks:", len(columns[0].Chunks()))
+
+	// Output:
+	// Rows: 5
+	// Values: [1 2 3 4 5]
+	// Chunks: 1
+}
+
+func Example_chunkedToRecordMismatch() {
+	// A three-row int64 column and a one-row string column
+	intBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer intBuilder.Release()
+	intBuilder.AppendValues([]int64{1, 2, 3}, nil)
+	ids := intBuilder.NewArray()
+	defer ids.Release()
+
+	strBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer strBuilder.Release()
+	strBuilder.Append("a")
+	names := strBuilder.NewArray()
+	defer names.Release()
+
+	idChunks := arrow.NewChunked(arrow.PrimitiveTypes.Int64, []arrow.Array{ids})
+	defer idChunks.Release()
+	nameChunks := arrow.NewChunked(arrow.BinaryTypes.String, []arrow.Array{names})
+	defer nameChunks.Release()
+
+	// Columns of different lengths are rejected
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil)
+	_, err := archery.ChunkedToRecord(schema, []*arrow.Chunked{idChunks, nameChunks})
+	fmt.Println("Error:", err)
+
+	// So are columns that do not match their field's type
+	wrongType := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.BinaryTypes.String}}, nil)
+	_, err = archery.ChunkedToRecord(wrongType, []*arrow.Chunked{idChunks})
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Error: column name has 1 rows, expected 3
+	// Error: column id has type int64, schema expects utf8
+}
+
+func Example_tableToRecord() {
+	// Create two record batches with the same schema
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+
+	builder.AppendValues([]int64{1, 2}, nil)
+	first := builder.NewArray()
+	defer first.Release()
+	batch1 := array.NewRecord(schema, []arrow.Array{first}, 2)
+	defer batch1.Release()
+
+	builder.AppendValues([]int64{3}, nil)
+	second := builder.NewArray()
+	defer second.Release()
+	batch2 := array.NewRecord(schema, []arrow.Array{second}, 1)
+	defer batch2.Release()
+
+	// A table as handed back by another Arrow producer
+	tbl := array.NewTableFromRecords(schema, []arrow.Record{batch1, batch2})
+	defer tbl.Release()
+
+	// Materialize it into one record
+	rec, err := archery.TableToRecord(tbl)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(rec)
+
+	// Wrap a record as a table
+	single := archery.NewTable(rec)
+	defer single.Release()
+
+	// Print the results
+	fmt.Println("Values:", rec.Column(0))
+	fmt.Println("Table rows:", single.NumRows())
+
+	// Output:
+	// Values: [1 2 3]
+	// Table rows: 3
+}
+
+func Example_compact() {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "value", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+
+	// Many tiny records as delivered by a streaming source
+	recs := make([]arrow.Record, 7)
+	for i := range recs {
+		builder.Append(int64(i))
+		col := builder.NewArray()
+		recs[i] = array.NewRecord(schema, []arrow.Array{col}, 1)
+		col.Release()
+	}
+	defer archery.ReleaseRecords(recs...)
+
+	// Coalesce them into batches of about three rows
+	ctx := context.Background()
+	batches, err := archery.Compact(ctx, recs, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecords(batches...)
+
+	for _, batch := range batches {
+		fmt.Println(batch.Column(0))
+	}
+
+	// Output:
+	// [0 1 2]
+	// [3 4 5]
+	// [6]
+}
+
+func Example_chunkRecord() {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "value", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{0, 1, 2, 3, 4, 5, 6}, nil)
+	col := builder.NewArray()
+	defer col.Release()
+	rec := array.NewRecord(schema, []arrow.Array{col}, 7)
+	defer rec.Release()
+
+	// Split into chunks of three rows for parallel workers
+	chunks, err := archery.ChunkRecord(rec, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecords(chunks...)
+
+	for _, chunk := range chunks {
+		fmt.Println(chunk.Column(0))
+	}
+
+	// A chunk size beyond the row count gives one chunk
+	whole, err := archery.ChunkRecord(rec, math.MaxInt64)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecords(whole...)
+	fmt.Println("Chunks:", len(whole), "rows:", whole[0].NumRows())
+
+	// Output:
+	// [0 1 2]
+	// [3 4 5]
+	// [6]
+	// Chunks: 1 rows: 7
+}
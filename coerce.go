@@ -0,0 +1,472 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// TYPE INFERENCE
+
+// inferSampleSize is the number of non-null values examined per column
+const inferSampleSize = 1000
+
+// dateLayout is the only date format recognized by type inference
+const dateLayout = "2006-01-02"
+
+// InferSchema returns the record's schema with each string column replaced by
+// the narrowest type that every sampled value parses as: Int64, Float64,
+// Boolean ("true"/"false", any case) or Date32 (YYYY-MM-DD), tried in that
+// order. Only the first 1000 non-null values of a column are examined. Columns
+// with no non-null values or with values matching none of the types stay
+// strings, as do all non-string columns.
+func InferSchema(rec arrow.Record) (*arrow.Schema, error) {
+	fields := make([]arrow.Field, rec.NumCols())
+	for i, field := range rec.Schema().Fields() {
+		fields[i] = field
+		if col, ok := rec.Column(i).(*array.String); ok {
+			fields[i].Type = inferStringType(col, inferSampleSize)
+		}
+	}
+
+	metadata := rec.Schema().Metadata()
+	return arrow.NewSchema(fields, &metadata), nil
+}
+
+// CoerceOptions controls how CoerceColumns cleans string columns
+type CoerceOptions struct {
+	// EmptyAsNull converts empty strings to nulls before inference, so that
+	// "" is treated as missing rather than as a value
+	EmptyAsNull bool
+}
+
+// CoerceColumns converts the record's string columns to the types chosen by
+// InferSchema. Because inference only samples each column, every value is
+// parsed again during conversion. When a value beyond the sample does not
+// parse, an Int64 column is widened to Float64 before giving up, and a column
+// that still does not parse is left as a string.
+func CoerceColumns(ctx context.Context, rec arrow.Record, opts ...CoerceOptions) (arrow.Record, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	if len(opts) == 1 && opts[0].EmptyAsNull {
+		cleaned, err := emptyStringColumnsToNull(ctx, rec)
+		if err != nil {
+			return nil, err
+		}
+		defer cleaned.Release()
+		rec = cleaned
+	}
+
+	inferred, err := InferSchema(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := inferred.Fields()
+	cols := make([]arrow.Array, rec.NumCols())
+	for i, field := range fields {
+		col := rec.Column(i)
+		strCol, ok := col.(*array.String)
+		if !ok || field.Type.ID() == arrow.STRING {
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+
+		converted, ok := parseStringColumn(memory.DefaultAllocator, strCol, field.Type)
+		if !ok && field.Type.ID() == arrow.INT64 {
+			// A value beyond the sample did not parse; it may still be a float
+			fields[i].Type = arrow.PrimitiveTypes.Float64
+			converted, ok = parseStringColumn(memory.DefaultAllocator, strCol, fields[i].Type)
+		}
+		if !ok {
+			fields[i].Type = arrow.BinaryTypes.String
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+		cols[i] = converted
+	}
+
+	metadata := inferred.Metadata()
+	result := array.NewRecord(arrow.NewSchema(fields, &metadata), cols, rec.NumRows())
+	for _, col := range cols {
+		col.Release()
+	}
+	return result, nil
+}
+
+// TYPE CONVERSION
+
+// Cast converts an array to the target type with Arrow's safe cast, which
+// rejects overflow, truncation of fractional values and unparsable strings. If
+// any element fails, the error names the first offending row. The caller is
+// responsible for releasing the result.
+func Cast(ctx context.Context, input arrow.Array, target arrow.DataType) (arrow.Array, error) {
+	if arrow.TypeEqual(input.DataType(), target) {
+		input.Retain()
+		return input, nil
+	}
+
+	result, err := compute.CastToType(ctx, input, target)
+	if err == nil {
+		return result, nil
+	}
+
+	// Recast one element at a time to find the row that failed
+	for i := 0; i < input.Len(); i++ {
+		elem := array.NewSlice(input, int64(i), int64(i+1))
+		single, elemErr := compute.CastToType(ctx, elem, target)
+		elem.Release()
+		if elemErr != nil {
+			return nil, fmt.Errorf("cannot cast %s to %s at row %d (value %s): %w",
+				input.DataType(), target, i, input.ValueStr(i), elemErr)
+		}
+		single.Release()
+	}
+	return nil, fmt.Errorf("cannot cast %s to %s: %w", input.DataType(), target, err)
+}
+
+// RetypeColumn returns a new record with the named column cast to the target
+// type by Cast and the schema updated to match. The field keeps its name,
+// nullability and metadata.
+func RetypeColumn(ctx context.Context, rec arrow.Record, colName string, target arrow.DataType) (arrow.Record, error) {
+	idx, err := GetColumnIndex(rec, colName)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := Cast(ctx, rec.Column(idx), target)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", colName, err)
+	}
+	defer converted.Release()
+
+	fields := rec.Schema().Fields()
+	fields[idx].Type = target
+	metadata := rec.Schema().Metadata()
+	schema := arrow.NewSchema(fields, &metadata)
+
+	cols := append([]arrow.Array{}, rec.Columns()...)
+	cols[idx] = converted
+	return array.NewRecord(schema, cols, rec.NumRows()), nil
+}
+
+// UnifyOptions controls how UnifyNumericColumns treats non-numeric columns
+type UnifyOptions struct {
+	// RejectNonNumeric makes any non-numeric column an error instead of
+	// leaving it untouched
+	RejectNonNumeric bool
+}
+
+// UnifyNumericColumns returns a new record with every integer and float
+// column cast to the numeric target type by Cast, e.g. all to Float64 before
+// a distance or correlation matrix. Non-numeric columns are left untouched
+// unless RejectNonNumeric is set. Fields keep their names, nullability and
+// metadata.
+func UnifyNumericColumns(ctx context.Context, rec arrow.Record, target arrow.DataType, opts ...UnifyOptions) (arrow.Record, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	var options UnifyOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+	if !isNumericType(target) {
+		return nil, fmt.Errorf("target type must be numeric, got %s", target)
+	}
+
+	fields := rec.Schema().Fields()
+	cols := make([]arrow.Array, len(fields))
+	for i, field := range fields {
+		if !isNumericType(field.Type) {
+			if options.RejectNonNumeric {
+				// Clean up already created columns
+				ReleaseArrays(cols[:i]...)
+				return nil, fmt.Errorf("column %s is not numeric: %s", field.Name, field.Type)
+			}
+			col := rec.Column(i)
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+
+		converted, err := Cast(ctx, rec.Column(i), target)
+		if err != nil {
+			// Clean up already created columns
+			ReleaseArrays(cols[:i]...)
+			return nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		cols[i] = converted
+		fields[i].Type = target
+	}
+
+	metadata := rec.Schema().Metadata()
+	result := array.NewRecord(arrow.NewSchema(fields, &metadata), cols, rec.NumRows())
+	ReleaseArrays(cols...)
+	return result, nil
+}
+
+// EmptyStringToNull returns a copy of a String or LargeString array with every
+// empty string replaced by null. Existing nulls are kept.
+func EmptyStringToNull(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+	switch arr := input.(type) {
+	case *array.String:
+		return emptyToNull(arr, array.NewStringBuilder(memory.DefaultAllocator)), nil
+	case *array.LargeString:
+		return emptyToNull(arr, array.NewLargeStringBuilder(memory.DefaultAllocator)), nil
+	default:
+		return nil, fmt.Errorf("expected a string array, got %s", input.DataType())
+	}
+}
+
+// emptyToNull copies the values of a string array into the builder, appending
+// nulls for empty strings
+func emptyToNull[B interface {
+	array.Builder
+	Append(string)
+}](arr valuer[string], builder B) arrow.Array {
+	defer builder.Release()
+	builder.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) || arr.Value(i) == "" {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(arr.Value(i))
+	}
+	return builder.NewArray()
+}
+
+// emptyStringColumnsToNull returns a copy of the record with EmptyStringToNull
+// applied to every string column
+func emptyStringColumnsToNull(ctx context.Context, rec arrow.Record) (arrow.Record, error) {
+	fields := rec.Schema().Fields()
+	cols := make([]arrow.Array, rec.NumCols())
+	for i, col := range rec.Columns() {
+		if !isStringType(col.DataType()) {
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+		cleaned, err := EmptyStringToNull(ctx, col)
+		if err != nil {
+			// Clean up already created columns
+			ReleaseArrays(cols[:i]...)
+			return nil, fmt.Errorf("column %s: %w", fields[i].Name, err)
+		}
+		cols[i] = cleaned
+		fields[i].Nullable = true
+	}
+
+	metadata := rec.Schema().Metadata()
+	result := array.NewRecord(arrow.NewSchema(fields, &metadata), cols, rec.NumRows())
+	ReleaseArrays(cols...)
+	return result, nil
+}
+
+// inferStringType returns the narrowest type that the first sampleSize non-null
+// values of the column all parse as
+func inferStringType(col *array.String, sampleSize int) arrow.DataType {
+	candidates := []arrow.DataType{
+		arrow.PrimitiveTypes.Int64,
+		arrow.PrimitiveTypes.Float64,
+		arrow.FixedWidthTypes.Boolean,
+		arrow.FixedWidthTypes.Date32,
+	}
+
+	sampled := 0
+	for i := 0; i < col.Len() && sampled < sampleSize && len(candidates) > 0; i++ {
+		if col.IsNull(i) {
+			continue
+		}
+		sampled++
+
+		// Keep only the candidates this value parses as
+		kept := candidates[:0]
+		for _, dt := range candidates {
+			if _, ok := parseString(col.Value(i), dt); ok {
+				kept = append(kept, dt)
+			}
+		}
+		candidates = kept
+	}
+
+	if sampled == 0 || len(candidates) == 0 {
+		return arrow.BinaryTypes.String
+	}
+	return candidates[0]
+}
+
+// parseStringColumn converts every value of the column to the given type,
+// reporting false if any non-null value does not parse
+func parseStringColumn(mem memory.Allocator, col *array.String, dt arrow.DataType) (arrow.Array, bool) {
+	builder := array.NewBuilder(mem, dt)
+	defer builder.Release()
+	builder.Reserve(col.Len())
+
+	for i := 0; i < col.Len(); i++ {
+		if col.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		val, ok := parseString(col.Value(i), dt)
+		if !ok {
+			return nil, false
+		}
+		switch b := builder.(type) {
+		case *array.Int64Builder:
+			b.Append(val.(int64))
+		case *array.Float64Builder:
+			b.Append(val.(float64))
+		case *array.BooleanBuilder:
+			b.Append(val.(bool))
+		case *array.Date32Builder:
+			b.Append(val.(arrow.Date32))
+		}
+	}
+	return builder.NewArray(), true
+}
+
+// parseString parses a string as one of the inferable types
+func parseString(s string, dt arrow.DataType) (interface{}, bool) {
+	s = strings.TrimSpace(s)
+	switch dt.ID() {
+	case arrow.INT64:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return v, err == nil
+	case arrow.FLOAT64:
+		v, err := strconv.ParseFloat(s, 64)
+		return v, err == nil
+	case arrow.BOOL:
+		switch {
+		case strings.EqualFold(s, "true"):
+			return true, true
+		case strings.EqualFold(s, "false"):
+			return false, true
+		}
+	case arrow.DATE32:
+		t, err := time.Parse(dateLayout, s)
+		return arrow.Date32FromTime(t), err == nil
+	}
+	return nil, false
+}
+
+// TYPE PROMOTION
+
+// CommonType returns the narrowest type that all the given types can be cast
+// to, and false if there is none. Identical types are their own common type,
+// and the null type combines with anything. Signed integers widen to the widest
+// signed input, unsigned integers to the widest unsigned input, and a mix of
+// the two to a signed type wide enough for both; uint64 has no common integer
+// type with signed integers. Integers combined with floats promote to Float64,
+// or to Float32 when every float is Float32 and no integer is wider than 16
+// bits. String and LargeString combine to LargeString. Any other mix of types,
+// such as a number with a string, has no common type.
+func CommonType(types ...arrow.DataType) (arrow.DataType, bool) {
+	var result arrow.DataType
+	for _, dt := range types {
+		switch {
+		case result == nil || result.ID() == arrow.NULL:
+			result = dt
+		case dt.ID() == arrow.NULL || arrow.TypeEqual(result, dt):
+		default:
+			promoted, ok := promotePair(result, dt)
+			if !ok {
+				return nil, false
+			}
+			result = promoted
+		}
+	}
+	return result, result != nil
+}
+
+// promotePair returns the common type of two distinct non-null types
+func promotePair(a, b arrow.DataType) (arrow.DataType, bool) {
+	switch {
+	case isStringType(a) && isStringType(b):
+		return arrow.BinaryTypes.LargeString, true
+	case arrow.IsFloating(a.ID()) || arrow.IsFloating(b.ID()):
+		if !isNumericType(a) || !isNumericType(b) {
+			return nil, false
+		}
+		if narrowForFloat32(a) && narrowForFloat32(b) {
+			return arrow.PrimitiveTypes.Float32, true
+		}
+		return arrow.PrimitiveTypes.Float64, true
+	case arrow.IsInteger(a.ID()) && arrow.IsInteger(b.ID()):
+		return promoteIntegers(a, b)
+	}
+	return nil, false
+}
+
+// promoteIntegers returns the narrowest integer type holding both integer types
+func promoteIntegers(a, b arrow.DataType) (arrow.DataType, bool) {
+	widthA := a.(arrow.FixedWidthDataType).BitWidth()
+	widthB := b.(arrow.FixedWidthDataType).BitWidth()
+	signedA, signedB := arrow.IsSignedInteger(a.ID()), arrow.IsSignedInteger(b.ID())
+
+	width := max(widthA, widthB)
+	if signedA != signedB {
+		// The signed type needs one more bit than the unsigned input
+		unsignedWidth := widthA
+		if signedA {
+			unsignedWidth = widthB
+		}
+		width = max(width, unsignedWidth*2)
+		if width > 64 {
+			return nil, false
+		}
+	}
+
+	signed := signedA || signedB
+	switch width {
+	case 8:
+		if signed {
+			return arrow.PrimitiveTypes.Int8, true
+		}
+		return arrow.PrimitiveTypes.Uint8, true
+	case 16:
+		if signed {
+			return arrow.PrimitiveTypes.Int16, true
+		}
+		return arrow.PrimitiveTypes.Uint16, true
+	case 32:
+		if signed {
+			return arrow.PrimitiveTypes.Int32, true
+		}
+		return arrow.PrimitiveTypes.Uint32, true
+	default:
+		if signed {
+			return arrow.PrimitiveTypes.Int64, true
+		}
+		return arrow.PrimitiveTypes.Uint64, true
+	}
+}
+
+// isStringType reports whether the type is String or LargeString
+func isStringType(dt arrow.DataType) bool {
+	return dt.ID() == arrow.STRING || dt.ID() == arrow.LARGE_STRING
+}
+
+// isNumericType reports whether the type is an integer or float type
+func isNumericType(dt arrow.DataType) bool {
+	return arrow.IsInteger(dt.ID()) || arrow.IsFloating(dt.ID())
+}
+
+// narrowForFloat32 reports whether the type converts to Float32 without loss
+func narrowForFloat32(dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.INT8, arrow.INT16, arrow.UINT8, arrow.UINT16:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_coerceColumns() {
+	// Create a record where every column is a string, as read from a CSV file
+	builder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+
+	columns := [][]string{
+		{"1", "2", "3"},
+		{"1.5", "2", "-0.25"},
+		{"true", "FALSE", "true"},
+		{"2024-01-31", "2024-02-29", "2024-03-31"},
+		{"a", "1", "b"},
+	}
+	names := []string{"id", "price", "active", "day", "code"}
+
+	fields := make([]arrow.Field, len(columns))
+	cols := make([]arrow.Array, len(columns))
+	for i, values := range columns {
+		builder.AppendValues(values, nil)
+		cols[i] = builder.NewArray()
+		defer cols[i].Release()
+		fields[i] = arrow.Field{Name: names[i], Type: arrow.BinaryTypes.String}
+	}
+	rec := array.NewRecord(arrow.NewSchema(fields, nil), cols, 3)
+	defer rec.Release()
+
+	// Convert the columns to their inferred types
+	ctx := context.Background()
+	typed, err := archery.CoerceColumns(ctx, rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(typed)
+
+	// Print the resulting column types
+	for _, field := range typed.Schema().Fields() {
+		fmt.Printf("%s: %s\n", field.Name, field.Type)
+	}
+
+	// Output:
+	// id: int64
+	// price: float64
+	// active: bool
+	// day: date32
+	// code: utf8
+}
+
+func Example_coerceColumnsBeyondSample() {
+	// Whole numbers for the first 1,000 rows, then fractional and text values
+	// that type inference does not sample
+	builder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	for i := 0; i < 1000; i++ {
+		builder.Append(strconv.Itoa(i))
+	}
+	builder.AppendValues([]string{"1000.5", "1001"}, nil)
+	amounts := builder.NewArray()
+	defer amounts.Release()
+	for i := 0; i < 1000; i++ {
+		builder.Append(strconv.Itoa(i))
+	}
+	builder.AppendValues([]string{"n/a", "1001"}, nil)
+	codes := builder.NewArray()
+	defer codes.Release()
+
+	rec, err := archery.ZipArrays([]string{"amount", "code"}, []arrow.Array{amounts, codes})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	// Both columns are sampled as integers
+	schema, err := archery.InferSchema(rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Inferred:", schema.Field(0).Type, schema.Field(1).Type)
+
+	// The fraction widens its column to float; the text keeps its column a string
+	ctx := context.Background()
+	typed, err := archery.CoerceColumns(ctx, rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(typed)
+	fmt.Println("Coerced:", typed.Schema().Field(0).Type, typed.Schema().Field(1).Type)
+	fmt.Println("Last amounts:", archery.ValueAt(typed.Column(0), 1000), archery.ValueAt(typed.Column(0), 1001))
+
+	// Output:
+	// Inferred: int64 int64
+	// Coerced: float64 utf8
+	// Last amounts: 1000.5 1001
+}
+
+func Example_commonType() {
+	// Find the promoted type for several combinations
+	combinations := [][]arrow.DataType{
+		{arrow.PrimitiveTypes.Int8, arrow.PrimitiveTypes.Int32},
+		{arrow.PrimitiveTypes.Uint16, arrow.PrimitiveTypes.Int8},
+		{arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Float32},
+		{arrow.PrimitiveTypes.Int16, arrow.PrimitiveTypes.Float32},
+		{arrow.PrimitiveTypes.Uint64, arrow.PrimitiveTypes.Int64},
+		{arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64},
+	}
+
+	for _, types := range combinations {
+		common, ok := archery.CommonType(types...)
+		if !ok {
+			fmt.Println(types, "-> none")
+			continue
+		}
+		fmt.Println(types, "->", common)
+	}
+
+	// Output:
+	// [int8 int32] -> int32
+	// [uint16 int8] -> int32
+	// [int64 float32] -> float64
+	// [int16 float32] -> float32
+	// [uint64 int64] -> none
+	// [utf8 int64] -> none
+}
+
+func Example_retypeColumn() {
+	// An id column that was loaded as float
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{101, 102, 103}, nil)
+	ids := builder.NewArray()
+	defer ids.Release()
+	builder.AppendValues([]float64{101, 102.5, 103}, nil)
+	badIDs := builder.NewArray()
+	defer badIDs.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Float64}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids}, 3)
+	defer rec.Release()
+	bad := array.NewRecord(schema, []arrow.Array{badIDs}, 3)
+	defer bad.Release()
+
+	// Make it int64
+	ctx := context.Background()
+	fixed, err := archery.RetypeColumn(ctx, rec, "id", arrow.PrimitiveTypes.Int64)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer fixed.Release()
+	fmt.Println("Type:", fixed.Schema().Field(0).Type)
+	fmt.Println("Values:", fixed.Column(0))
+
+	// A fractional id cannot be converted safely
+	_, err = archery.RetypeColumn(ctx, bad, "id", arrow.PrimitiveTypes.Int64)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Type: int64
+	// Values: [101 102 103]
+	// Error: column id: cannot cast float64 to int64 at row 1 (value 102.5): invalid: float value 102.500000 was truncated converting to int64
+}
+
+func Example_unifyNumericColumns() {
+	// A feature record with mixed numeric types and a label
+	countBuilder := array.NewInt32Builder(memory.DefaultAllocator)
+	defer countBuilder.Release()
+	countBuilder.AppendValues([]int32{3, 5}, nil)
+	counts := countBuilder.NewArray()
+	defer counts.Release()
+
+	ratioBuilder := array.NewFloat32Builder(memory.DefaultAllocator)
+	defer ratioBuilder.Release()
+	ratioBuilder.AppendValues([]float32{0.5, 0.25}, nil)
+	ratios := ratioBuilder.NewArray()
+	defer ratios.Release()
+
+	labelBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer labelBuilder.Release()
+	labelBuilder.AppendValues([]string{"x", "y"}, nil)
+	labels := labelBuilder.NewArray()
+	defer labels.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "count", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "ratio", Type: arrow.PrimitiveTypes.Float32},
+		{Name: "label", Type: arrow.BinaryTypes.String},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{counts, ratios, labels}, 2)
+	defer rec.Release()
+
+	// Cast every numeric column to float64, leaving the label alone
+	ctx := context.Background()
+	unified, err := archery.UnifyNumericColumns(ctx, rec, arrow.PrimitiveTypes.Float64)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer unified.Release()
+	for _, field := range unified.Schema().Fields() {
+		fmt.Printf("%s: %s\n", field.Name, field.Type)
+	}
+
+	// Or insist that every column is numeric
+	_, err = archery.UnifyNumericColumns(ctx, rec, arrow.PrimitiveTypes.Float64, archery.UnifyOptions{RejectNonNumeric: true})
+	fmt.Println("Error:", err)
+
+	// Output:
+	// count: float64
+	// ratio: float64
+	// label: utf8
+	// Error: column label is not numeric: utf8
+}
+
+func Example_emptyStringToNull() {
+	// A category column where "" means missing
+	builder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]string{"red", "", "blue", ""}, []bool{true, true, true, false})
+	colors := builder.NewArray()
+	defer colors.Release()
+
+	ctx := context.Background()
+	cleaned, err := archery.EmptyStringToNull(ctx, colors)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer cleaned.Release()
+	fmt.Println("Nulls before:", colors.NullN(), "after:", cleaned.NullN())
+
+	// The same cleaning during coercion lets a column with gaps be typed
+	builder.AppendValues([]string{"10", "", "30"}, nil)
+	counts := builder.NewArray()
+	defer counts.Release()
+	schema := arrow.NewSchema([]arrow.Field{{Name: "count", Type: arrow.BinaryTypes.String}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{counts}, 3)
+	defer rec.Release()
+
+	typed, err := archery.CoerceColumns(ctx, rec, archery.CoerceOptions{EmptyAsNull: true})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer typed.Release()
+	fmt.Println("Type:", typed.Schema().Field(0).Type)
+	fmt.Println("Values:", typed.Column(0))
+
+	// Output:
+	// Nulls before: 1 after: 2
+	// Type: int64
+	// Values: [10 (null) 30]
+}
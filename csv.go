@@ -0,0 +1,241 @@
+package archery
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// CSV READING
+
+// CSVOptions controls how ReadCSVBatches parses its input
+type CSVOptions struct {
+	// Schema fixes the column names and types instead of inferring them from
+	// the first batch. Supported types are String, Int64, Float64, Boolean and
+	// Date32, and the schema must have one field per CSV column.
+	Schema *arrow.Schema
+	// Comma is the field delimiter. Zero means ','.
+	Comma rune
+	// Allocator allocates the batches. Nil means the default allocator.
+	Allocator memory.Allocator
+}
+
+// ReadCSVBatches returns a record reader that parses CSV from r in batches of
+// up to batchSize rows, so only one batch is held in memory at a time. The
+// first line is a header naming the columns. Unless a schema is given, column
+// types are inferred from the first batch as InferSchema does, and later
+// values that do not parse as the inferred type make Next return false with
+// the error available from Err. Empty fields are read as nulls. The caller is
+// responsible for releasing the reader.
+func ReadCSVBatches(r io.Reader, batchSize int, opts ...CSVOptions) (array.RecordReader, error) {
+	if batchSize < 1 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	var options CSVOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+
+	cr := csv.NewReader(r)
+	if options.Comma != 0 {
+		cr.Comma = options.Comma
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("csv: missing header")
+		}
+		return nil, fmt.Errorf("csv: reading header: %w", err)
+	}
+	cr.FieldsPerRecord = len(header)
+
+	mem := options.Allocator
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	reader := &csvBatchReader{refCount: 1, csv: cr, mem: mem, batchSize: batchSize}
+
+	if options.Schema != nil {
+		if options.Schema.NumFields() != len(header) {
+			return nil, fmt.Errorf("csv: schema has %d fields, header has %d columns", options.Schema.NumFields(), len(header))
+		}
+		for _, field := range options.Schema.Fields() {
+			switch field.Type.ID() {
+			case arrow.STRING, arrow.INT64, arrow.FLOAT64, arrow.BOOL, arrow.DATE32:
+			default:
+				return nil, fmt.Errorf("csv: unsupported type %s for column %s", field.Type, field.Name)
+			}
+		}
+		reader.schema = options.Schema
+		return reader, nil
+	}
+
+	// Infer the schema from the first batch, which is kept for the first Next
+	rows, err := reader.readRows()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]arrow.Field, len(header))
+	for i, name := range header {
+		fields[i] = arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true}
+	}
+	first := buildStringRecord(reader.mem, arrow.NewSchema(fields, nil), rows)
+	defer first.Release()
+
+	reader.schema, err = InferSchema(first)
+	if err != nil {
+		return nil, err
+	}
+	reader.pending = rows
+	return reader, nil
+}
+
+// csvBatchReader is the array.RecordReader returned by ReadCSVBatches
+type csvBatchReader struct {
+	refCount  int64
+	csv       *csv.Reader
+	mem       memory.Allocator
+	schema    *arrow.Schema
+	batchSize int
+	pending   [][]string
+	rowOffset int64
+	cur       arrow.Record
+	err       error
+	done      bool
+}
+
+func (r *csvBatchReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+func (r *csvBatchReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		ReleaseRecord(r.cur)
+		r.cur = nil
+	}
+}
+
+func (r *csvBatchReader) Schema() *arrow.Schema { return r.schema }
+
+func (r *csvBatchReader) Record() arrow.Record { return r.cur }
+
+func (r *csvBatchReader) Err() error { return r.err }
+
+func (r *csvBatchReader) Next() bool {
+	ReleaseRecord(r.cur)
+	r.cur = nil
+	if r.done || r.err != nil {
+		return false
+	}
+
+	rows := r.pending
+	r.pending = nil
+	if rows == nil {
+		var err error
+		if rows, err = r.readRows(); err != nil {
+			r.err = err
+			return false
+		}
+	}
+	if len(rows) == 0 {
+		r.done = true
+		return false
+	}
+
+	rec, err := r.buildRecord(rows)
+	if err != nil {
+		r.err = err
+		return false
+	}
+	r.cur = rec
+	r.rowOffset += int64(len(rows))
+	return true
+}
+
+// readRows reads up to batchSize data rows
+func (r *csvBatchReader) readRows() ([][]string, error) {
+	rows := make([][]string, 0, r.batchSize)
+	for len(rows) < r.batchSize {
+		row, err := r.csv.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// buildRecord parses one batch of rows into a record of the reader's schema
+func (r *csvBatchReader) buildRecord(rows [][]string) (arrow.Record, error) {
+	fields := r.schema.Fields()
+	for i := range fields {
+		fields[i].Type = arrow.BinaryTypes.String
+	}
+	raw := buildStringRecord(r.mem, arrow.NewSchema(fields, nil), rows)
+	defer raw.Release()
+
+	cols := make([]arrow.Array, len(fields))
+	for i, field := range r.schema.Fields() {
+		col := raw.Column(i).(*array.String)
+		if field.Type.ID() == arrow.STRING {
+			col.Retain()
+			cols[i] = col
+			continue
+		}
+
+		converted, ok := parseStringColumn(r.mem, col, field.Type)
+		if !ok {
+			// Clean up already created columns
+			for j := 0; j < i; j++ {
+				cols[j].Release()
+			}
+			return nil, fmt.Errorf("csv: column %s has a value that is not %s in rows %d to %d",
+				field.Name, field.Type, r.rowOffset, r.rowOffset+int64(len(rows))-1)
+		}
+		cols[i] = converted
+	}
+
+	rec := array.NewRecord(r.schema, cols, int64(len(rows)))
+	for _, col := range cols {
+		col.Release()
+	}
+	return rec, nil
+}
+
+// buildStringRecord builds a record of string columns from CSV rows, reading
+// empty fields as nulls
+func buildStringRecord(mem memory.Allocator, schema *arrow.Schema, rows [][]string) arrow.Record {
+	cols := make([]arrow.Array, schema.NumFields())
+	for i := range cols {
+		builder := array.NewStringBuilder(mem)
+		builder.Reserve(len(rows))
+		for _, row := range rows {
+			if row[i] == "" {
+				builder.AppendNull()
+				continue
+			}
+			builder.Append(row[i])
+		}
+		cols[i] = builder.NewArray()
+		builder.Release()
+	}
+
+	rec := array.NewRecord(schema, cols, int64(len(rows)))
+	for _, col := range cols {
+		col.Release()
+	}
+	return rec
+}
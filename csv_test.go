@@ -0,0 +1,48 @@
+package archery_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TFMV/archery"
+)
+
+func Example_readCSVBatches() {
+	data := `id,name,score,joined
+1,ann,9.5,2024-01-02
+2,bob,,2024-02-03
+3,cy,7.25,2024-03-04
+4,dee,8,2024-04-05
+5,eve,6.5,2024-05-06
+`
+
+	// Stream the file two rows at a time
+	reader, err := archery.ReadCSVBatches(strings.NewReader(data), 2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer reader.Release()
+
+	fmt.Println("Schema:")
+	for _, field := range reader.Schema().Fields() {
+		fmt.Printf("  %s: %s\n", field.Name, field.Type)
+	}
+	for reader.Next() {
+		rec := reader.Record()
+		fmt.Println("Batch:", rec.NumRows(), "rows, scores", rec.Column(2))
+	}
+	if err := reader.Err(); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Output:
+	// Schema:
+	//   id: int64
+	//   name: utf8
+	//   score: float64
+	//   joined: date32
+	// Batch: 2 rows, scores [9.5 (null)]
+	// Batch: 2 rows, scores [7.25 8]
+	// Batch: 1 rows, scores [6.5]
+}
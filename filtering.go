@@ -3,10 +3,14 @@ package archery
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/bitutil"
 	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
 )
 
 // ARRAY FILTERING OPERATIONS
@@ -56,6 +60,33 @@ func LessEqual(ctx context.Context, a, b arrow.Array) (arrow.Array, error) {
 	return callFunction(ctx, "less_equal", a, b)
 }
 
+// EqualNullSafe returns a mask array indicating which elements are equal, treating
+// two nulls as equal and a null compared with a value as not equal, like SQL's
+// IS NOT DISTINCT FROM. The result never contains nulls.
+func EqualNullSafe(ctx context.Context, a, b arrow.Array) (arrow.Array, error) {
+	eq, err := Equal(ctx, a, b)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(eq)
+
+	eqArr := eq.(*array.Boolean)
+	mem, _ := contextAllocator(ctx)
+	builder := array.NewBooleanBuilder(mem)
+	defer builder.Release()
+	builder.Reserve(eqArr.Len())
+	for i := 0; i < eqArr.Len(); i++ {
+		nullA, nullB := a.IsNull(i), b.IsNull(i)
+		switch {
+		case nullA || nullB:
+			builder.Append(nullA && nullB)
+		default:
+			builder.Append(eqArr.Value(i))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
 // And performs logical AND operation on two boolean arrays
 func And(ctx context.Context, a, b arrow.Array) (arrow.Array, error) {
 	return callFunction(ctx, "and", a, b)
@@ -71,49 +102,233 @@ func Xor(ctx context.Context, a, b arrow.Array) (arrow.Array, error) {
 	return callFunction(ctx, "xor", a, b)
 }
 
-// Invert performs logical NOT operation on a boolean array
+// Invert performs logical NOT operation on a boolean array. Nulls stay null.
 func Invert(ctx context.Context, input arrow.Array) (arrow.Array, error) {
-	return callFunction(ctx, "invert", input)
+	// arrow-go registers no invert kernel, so negate the values directly
+	// TODO(archery): replace with compute.invert when supported
+	boolArr, ok := input.(*array.Boolean)
+	if !ok {
+		return nil, fmt.Errorf("invert requires a boolean array, got %s", input.DataType())
+	}
+
+	mem, _ := contextAllocator(ctx)
+	builder := array.NewBooleanBuilder(mem)
+	defer builder.Release()
+	builder.Reserve(boolArr.Len())
+	for i := 0; i < boolArr.Len(); i++ {
+		if boolArr.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(!boolArr.Value(i))
+	}
+	return builder.NewArray(), nil
 }
 
-// SCALAR COMPARISON OPERATIONS
+// PopCount returns the number of true, non-null values in a boolean mask. It
+// counts set bits over the packed bitmaps a word at a time rather than
+// testing each element.
+func PopCount(mask *array.Boolean) int64 {
+	if mask.Len() == 0 {
+		return 0
+	}
+	data := mask.Data()
+	values := data.Buffers()[1].Bytes()
+	offset := data.Offset()
+	if mask.NullN() == 0 {
+		return int64(bitutil.CountSetBits(values, offset, mask.Len()))
+	}
 
-// EqualScalar returns a mask array indicating which elements are equal to the scalar value
-func EqualScalar(ctx context.Context, input arrow.Array, val interface{}) (arrow.Array, error) {
-	// Convert the scalar value to an Arrow scalar
-	sc, err := toArrowScalar(val, input.DataType())
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert scalar: %w", err)
+	// Only count bits that are both true and valid
+	length := int64(mask.Len())
+	both := make([]byte, bitutil.BytesForBits(length))
+	bitutil.BitmapAnd(values, data.Buffers()[0].Bytes(), int64(offset), int64(offset), both, 0, length)
+	return int64(bitutil.CountSetBits(both, 0, mask.Len()))
+}
+
+// IndicesToMask returns a boolean mask of the given length that is true at the
+// given row indices and false elsewhere, allocated from mem or the default
+// allocator when mem is nil. Repeated indices are allowed; an index outside
+// [0, length) is an error. It is the inverse of selecting rows by a mask.
+func IndicesToMask(indices []int64, length int, mem memory.Allocator) (*array.Boolean, error) {
+	if length < 0 {
+		return nil, fmt.Errorf("mask length must be non-negative, got %d", length)
+	}
+	if mem == nil {
+		mem = memory.DefaultAllocator
 	}
 
-	// Call the function
-	result, err := compute.CallFunction(ctx, "equal", nil, compute.NewDatum(input), compute.NewDatum(sc))
+	// Setting a bit twice is harmless, so duplicates need no separate pass
+	bits := make([]byte, bitutil.BytesForBits(int64(length)))
+	for _, idx := range indices {
+		if idx < 0 || idx >= int64(length) {
+			return nil, fmt.Errorf("index %d out of range (0-%d)", idx, length-1)
+		}
+		bitutil.SetBit(bits, int(idx))
+	}
+
+	builder := array.NewBooleanBuilder(mem)
+	defer builder.Release()
+	builder.Reserve(length)
+	for i := 0; i < length; i++ {
+		builder.UnsafeAppend(bitutil.BitIsSet(bits, i))
+	}
+	return builder.NewBooleanArray(), nil
+}
+
+// IndicesWhere returns the positions of input where the predicate returns
+// true, in ascending order. Null elements are skipped without calling the
+// predicate, as in FilterRecordByPredicate. Built into an Int64 array, the
+// result can be passed to TakeWithIndices to gather the same positions from a
+// parallel array without materializing a filtered copy of input.
+func IndicesWhere(ctx context.Context, input arrow.Array, predicate func(arr arrow.Array, i int) bool) ([]int64, error) {
+	indices := make([]int64, 0)
+	for i := 0; i < input.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if input.IsValid(i) && predicate(input, i) {
+			indices = append(indices, int64(i))
+		}
+	}
+	return indices, nil
+}
+
+// IsIn returns a mask array indicating which elements occur in valueSet. Nulls
+// never match, so null elements map to false.
+func IsIn(ctx context.Context, input arrow.Array, valueSet arrow.Array) (arrow.Array, error) {
+	opts := compute.SetOptions{
+		ValueSet:     compute.NewDatum(valueSet),
+		NullBehavior: compute.NullMatchingSkip,
+	}
+	defer opts.ValueSet.Release()
+
+	result, err := compute.IsIn(ctx, opts, compute.NewDatum(input))
 	if err != nil {
-		return nil, fmt.Errorf("failed to compare with scalar: %w", err)
+		return nil, fmt.Errorf("failed to call is_in: %w", err)
 	}
+	defer result.Release()
 
 	return result.(*compute.ArrayDatum).MakeArray(), nil
 }
 
+// SCALAR COMPARISON OPERATIONS
+//
+// The scalar comparison functions accept an optional compute.FunctionOptions
+// value which is forwarded to the underlying comparison kernel.
+
+// EqualScalar returns a mask array indicating which elements are equal to the scalar value
+func EqualScalar(ctx context.Context, input arrow.Array, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	return compareScalar(ctx, "equal", input, val, opts)
+}
+
 // NotEqualScalar returns a mask array indicating which elements are not equal to the scalar value
-func NotEqualScalar(ctx context.Context, input arrow.Array, val interface{}) (arrow.Array, error) {
-	// Convert the scalar value to an Arrow scalar
-	sc, err := toArrowScalar(val, input.DataType())
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert scalar: %w", err)
+func NotEqualScalar(ctx context.Context, input arrow.Array, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	return compareScalar(ctx, "not_equal", input, val, opts)
+}
+
+// GreaterScalar returns a mask array indicating which elements are greater than the scalar value
+func GreaterScalar(ctx context.Context, input arrow.Array, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	return compareScalar(ctx, "greater", input, val, opts)
+}
+
+// GreaterEqualScalar returns a mask array indicating which elements are greater than or equal to the scalar value
+func GreaterEqualScalar(ctx context.Context, input arrow.Array, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	return compareScalar(ctx, "greater_equal", input, val, opts)
+}
+
+// LessScalar returns a mask array indicating which elements are less than the scalar value
+func LessScalar(ctx context.Context, input arrow.Array, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	return compareScalar(ctx, "less", input, val, opts)
+}
+
+// LessEqualScalar returns a mask array indicating which elements are less than or equal to the scalar value
+func LessEqualScalar(ctx context.Context, input arrow.Array, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	return compareScalar(ctx, "less_equal", input, val, opts)
+}
+
+// CompareOp identifies a comparison operator
+type CompareOp int
+
+const (
+	// EQ compares for equality
+	EQ CompareOp = iota
+	// NE compares for inequality
+	NE
+	// GT compares for greater than
+	GT
+	// GE compares for greater than or equal
+	GE
+	// LT compares for less than
+	LT
+	// LE compares for less than or equal
+	LE
+)
+
+// funcName returns the name of the compute function implementing the operator
+func (op CompareOp) funcName() (string, error) {
+	switch op {
+	case EQ:
+		return "equal", nil
+	case NE:
+		return "not_equal", nil
+	case GT:
+		return "greater", nil
+	case GE:
+		return "greater_equal", nil
+	case LT:
+		return "less", nil
+	case LE:
+		return "less_equal", nil
+	default:
+		return "", fmt.Errorf("unknown comparison operator: %d", op)
 	}
+}
 
-	// Call the function
-	result, err := compute.CallFunction(ctx, "not_equal", nil, compute.NewDatum(input), compute.NewDatum(sc))
+// CompareScalar returns a mask array comparing each element to the scalar value with op
+func CompareScalar(ctx context.Context, input arrow.Array, op CompareOp, val interface{}, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	funcName, err := op.funcName()
 	if err != nil {
-		return nil, fmt.Errorf("failed to compare with scalar: %w", err)
+		return nil, err
+	}
+	return compareScalar(ctx, funcName, input, val, opts)
+}
+
+// CompareScalars returns one mask array per operator/value pair, each comparing
+// the input against the corresponding scalar value. Options, if given, are
+// passed to every comparison.
+func CompareScalars(ctx context.Context, input arrow.Array, ops []CompareOp, vals []interface{}, opts ...compute.FunctionOptions) ([]arrow.Array, error) {
+	if len(ops) != len(vals) {
+		return nil, fmt.Errorf("number of operators (%d) does not match number of values (%d)",
+			len(ops), len(vals))
+	}
+	if _, err := functionOptions(opts); err != nil {
+		return nil, err
 	}
 
-	return result.(*compute.ArrayDatum).MakeArray(), nil
+	masks := make([]arrow.Array, len(ops))
+	for i, op := range ops {
+		mask, err := CompareScalar(ctx, input, op, vals[i], opts...)
+		if err != nil {
+			// Clean up already created masks
+			for j := 0; j < i; j++ {
+				masks[j].Release()
+			}
+			return nil, fmt.Errorf("error comparing with value %d: %w", i, err)
+		}
+		masks[i] = mask
+	}
+	return masks, nil
 }
 
-// GreaterScalar returns a mask array indicating which elements are greater than the scalar value
-func GreaterScalar(ctx context.Context, input arrow.Array, val interface{}) (arrow.Array, error) {
+// compareScalar calls the named comparison function with the input array and
+// a scalar converted to the input's type
+func compareScalar(ctx context.Context, funcName string, input arrow.Array, val interface{}, opts []compute.FunctionOptions) (arrow.Array, error) {
+	fnOpts, err := functionOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert the scalar value to an Arrow scalar
 	sc, err := toArrowScalar(val, input.DataType())
 	if err != nil {
@@ -121,63 +336,226 @@ func GreaterScalar(ctx context.Context, input arrow.Array, val interface{}) (arr
 	}
 
 	// Call the function
-	result, err := compute.CallFunction(ctx, "greater", nil, compute.NewDatum(input), compute.NewDatum(sc))
+	result, err := compute.CallFunction(ctx, funcName, fnOpts, compute.NewDatum(input), compute.NewDatum(sc))
 	if err != nil {
 		return nil, fmt.Errorf("failed to compare with scalar: %w", err)
 	}
+	defer result.Release()
 
 	return result.(*compute.ArrayDatum).MakeArray(), nil
 }
 
-// GreaterEqualScalar returns a mask array indicating which elements are greater than or equal to the scalar value
-func GreaterEqualScalar(ctx context.Context, input arrow.Array, val interface{}) (arrow.Array, error) {
-	// Convert the scalar value to an Arrow scalar
-	sc, err := toArrowScalar(val, input.DataType())
+// functionOptions returns the single optional options value, or nil when none
+// is given
+func functionOptions(opts []compute.FunctionOptions) (compute.FunctionOptions, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	if len(opts) == 1 {
+		return opts[0], nil
+	}
+	return nil, nil
+}
+
+// PERCENTILE FILTERING OPERATIONS
+
+// FilterAbovePercentile returns the elements greater than or equal to the
+// pct-th percentile of the array. Pct is a percentage in [0, 100], so 90 is
+// Quantile's 0.9. Nulls are dropped.
+func FilterAbovePercentile(ctx context.Context, input arrow.Array, pct float64) (arrow.Array, error) {
+	mask, err := percentileMask(ctx, input, pct, GE)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert scalar: %w", err)
+		return nil, err
 	}
+	defer ReleaseArray(mask)
 
-	// Call the function
-	result, err := compute.CallFunction(ctx, "greater_equal", nil, compute.NewDatum(input), compute.NewDatum(sc))
+	return Filter(ctx, input, mask)
+}
+
+// FilterBelowPercentile returns the elements less than or equal to the
+// pct-th percentile of the array. Pct is a percentage in [0, 100], so 10 is
+// Quantile's 0.1. Nulls are dropped.
+func FilterBelowPercentile(ctx context.Context, input arrow.Array, pct float64) (arrow.Array, error) {
+	mask, err := percentileMask(ctx, input, pct, LE)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compare with scalar: %w", err)
+		return nil, err
 	}
+	defer ReleaseArray(mask)
 
-	return result.(*compute.ArrayDatum).MakeArray(), nil
+	return Filter(ctx, input, mask)
 }
 
-// LessScalar returns a mask array indicating which elements are less than the scalar value
-func LessScalar(ctx context.Context, input arrow.Array, val interface{}) (arrow.Array, error) {
-	// Convert the scalar value to an Arrow scalar
-	sc, err := toArrowScalar(val, input.DataType())
+// percentileMask compares each element against the pct-th percentile of the
+// array, with pct a percentage in [0, 100]
+func percentileMask(ctx context.Context, input arrow.Array, pct float64, op CompareOp) (arrow.Array, error) {
+	if pct < 0 || pct > 100 || math.IsNaN(pct) {
+		return nil, fmt.Errorf("percentile must be a percentage in [0, 100], got %v", pct)
+	}
+
+	threshold, err := Quantile(ctx, input, pct/100)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert scalar: %w", err)
+		return nil, err
 	}
 
-	// Call the function
-	result, err := compute.CallFunction(ctx, "less", nil, compute.NewDatum(input), compute.NewDatum(sc))
+	// Compare in float64 so an interpolated threshold is not truncated
+	values, err := castFloat64(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compare with scalar: %w", err)
+		return nil, err
 	}
+	defer values.Release()
 
-	return result.(*compute.ArrayDatum).MakeArray(), nil
+	return CompareScalar(ctx, values, op, threshold)
 }
 
-// LessEqualScalar returns a mask array indicating which elements are less than or equal to the scalar value
-func LessEqualScalar(ctx context.Context, input arrow.Array, val interface{}) (arrow.Array, error) {
-	// Convert the scalar value to an Arrow scalar
-	sc, err := toArrowScalar(val, input.DataType())
+// CONDITIONAL OPERATIONS
+
+// Where returns an array taking elements from ifTrue where the mask is true and
+// from ifFalse otherwise. A null in the mask produces a null in the output.
+func Where(ctx context.Context, mask arrow.Array, ifTrue, ifFalse arrow.Array) (arrow.Array, error) {
+	if mask.DataType().ID() != arrow.BOOL {
+		return nil, fmt.Errorf("mask must be a boolean array, got %s", mask.DataType())
+	}
+	if mask.Len() != ifTrue.Len() || mask.Len() != ifFalse.Len() {
+		return nil, fmt.Errorf("mask (%d), true (%d) and false (%d) lengths must match",
+			mask.Len(), ifTrue.Len(), ifFalse.Len())
+	}
+	if !arrow.TypeEqual(ifTrue.DataType(), ifFalse.DataType()) {
+		return nil, fmt.Errorf("branch types must match: %s vs %s", ifTrue.DataType(), ifFalse.DataType())
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := compute.CallFunction(ctx, "if_else", nil,
+		compute.NewDatum(mask), compute.NewDatum(ifTrue), compute.NewDatum(ifFalse))
+	if err == nil {
+		// compute-upgraded
+		defer result.Release()
+		return datumToArray(result), nil
+	}
+	if !kernelUnavailable(err) {
+		return nil, fmt.Errorf("failed to call if_else: %w", err)
+	}
+	// compute.if_else not available – fallback
+	// TODO(archery): replace with compute.if_else when supported
+
+	// Select from the concatenation of both branches by index
+	mem, _ := contextAllocator(ctx)
+	combined, err := array.Concatenate([]arrow.Array{ifTrue, ifFalse}, mem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine branches: %w", err)
+	}
+	defer combined.Release()
+
+	boolMask := mask.(*array.Boolean)
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.Reserve(boolMask.Len())
+	for i := 0; i < boolMask.Len(); i++ {
+		switch {
+		case boolMask.IsNull(i):
+			builder.AppendNull()
+		case boolMask.Value(i):
+			builder.Append(int64(i))
+		default:
+			builder.Append(int64(boolMask.Len() + i))
+		}
+	}
+	indices := builder.NewArray()
+	defer indices.Release()
+
+	return TakeWithIndices(ctx, combined, indices)
+}
+
+// WhereScalar is like Where but uses the scalar value ifFalse wherever the mask is not true
+func WhereScalar(ctx context.Context, mask arrow.Array, ifTrue arrow.Array, ifFalse interface{}) (arrow.Array, error) {
+	sc, err := toArrowScalar(ifFalse, ifTrue.DataType())
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert scalar: %w", err)
 	}
 
-	// Call the function
-	result, err := compute.CallFunction(ctx, "less_equal", nil, compute.NewDatum(input), compute.NewDatum(sc))
+	mem, _ := contextAllocator(ctx)
+	falseArr, err := scalar.MakeArrayFromScalar(sc, ifTrue.Len(), mem)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compare with scalar: %w", err)
+		return nil, fmt.Errorf("failed to broadcast scalar: %w", err)
 	}
+	defer falseArr.Release()
 
-	return result.(*compute.ArrayDatum).MakeArray(), nil
+	return Where(ctx, mask, ifTrue, falseArr)
+}
+
+// CaseWhen evaluates conditions in order and, for each element, takes the value
+// from the choice paired with the first true condition. Elements matching no
+// condition take the value from defaultVal, or null if defaultVal is nil. Null
+// conditions are treated as false, as in SQL.
+func CaseWhen(ctx context.Context, conditions []arrow.Array, choices []arrow.Array, defaultVal arrow.Array) (arrow.Array, error) {
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("no conditions specified")
+	}
+	if len(conditions) != len(choices) {
+		return nil, fmt.Errorf("number of conditions (%d) does not match number of choices (%d)",
+			len(conditions), len(choices))
+	}
+
+	length := conditions[0].Len()
+	dataType := choices[0].DataType()
+	branches := make([]arrow.Array, 0, len(choices)+1)
+	for i := range conditions {
+		if conditions[i].DataType().ID() != arrow.BOOL {
+			return nil, fmt.Errorf("condition %d must be a boolean array, got %s", i, conditions[i].DataType())
+		}
+		if conditions[i].Len() != length || choices[i].Len() != length {
+			return nil, fmt.Errorf("condition %d and its choice must have length %d", i, length)
+		}
+		if !arrow.TypeEqual(choices[i].DataType(), dataType) {
+			return nil, fmt.Errorf("choice %d has type %s, expected %s", i, choices[i].DataType(), dataType)
+		}
+		branches = append(branches, choices[i])
+	}
+	if defaultVal != nil {
+		if defaultVal.Len() != length {
+			return nil, fmt.Errorf("default must have length %d, got %d", length, defaultVal.Len())
+		}
+		if !arrow.TypeEqual(defaultVal.DataType(), dataType) {
+			return nil, fmt.Errorf("default has type %s, expected %s", defaultVal.DataType(), dataType)
+		}
+		branches = append(branches, defaultVal)
+	}
+
+	// Select from the concatenation of all branches by index
+	mem, _ := contextAllocator(ctx)
+	combined, err := array.Concatenate(branches, mem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine choices: %w", err)
+	}
+	defer combined.Release()
+
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.Reserve(length)
+	for i := 0; i < length; i++ {
+		matched := false
+		for k, cond := range conditions {
+			boolCond := cond.(*array.Boolean)
+			if boolCond.IsValid(i) && boolCond.Value(i) {
+				builder.Append(int64(k*length + i))
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if defaultVal != nil {
+			builder.Append(int64(len(choices)*length + i))
+		} else {
+			builder.AppendNull()
+		}
+	}
+	indices := builder.NewArray()
+	defer indices.Release()
+
+	return TakeWithIndices(ctx, combined, indices)
 }
 
 // RECORD OPERATIONS
@@ -190,6 +568,11 @@ func FilterRecord(ctx context.Context, input arrow.Record, mask arrow.Array) (ar
 			mask.Len(), input.NumRows())
 	}
 
+	boolMask, ok := mask.(*array.Boolean)
+	if !ok {
+		return nil, fmt.Errorf("mask must be a boolean array, got %s", mask.DataType())
+	}
+
 	// Filter each column
 	cols := make([]arrow.Array, input.NumCols())
 	for i := 0; i < int(input.NumCols()); i++ {
@@ -207,7 +590,7 @@ func FilterRecord(ctx context.Context, input arrow.Record, mask arrow.Array) (ar
 
 	// Create new record batch
 	schema := input.Schema()
-	result := array.NewRecord(schema, cols, int64(cols[0].Len()))
+	result := array.NewRecord(schema, cols, PopCount(boolMask))
 
 	// Release the columns (record takes ownership)
 	for _, col := range cols {
@@ -217,8 +600,9 @@ func FilterRecord(ctx context.Context, input arrow.Record, mask arrow.Array) (ar
 	return result, nil
 }
 
-// FilterRecordByColumn returns a new record with only rows where the condition on the column is true
-func FilterRecordByColumn(ctx context.Context, input arrow.Record, colName string, condition arrow.Array) (arrow.Record, error) {
+// FilterRecordByMaskColumn returns a new record with only rows where the
+// pre-built condition mask is true. The named column must exist in the record.
+func FilterRecordByMaskColumn(ctx context.Context, input arrow.Record, colName string, condition arrow.Array) (arrow.Record, error) {
 	// Get column by name
 	col, err := GetColumn(input, colName)
 	if err != nil {
@@ -230,6 +614,120 @@ func FilterRecordByColumn(ctx context.Context, input arrow.Record, colName strin
 	return FilterRecord(ctx, input, condition)
 }
 
+// FilterRecordByColumn returns a new record with only rows where the condition on the column is true
+//
+// Deprecated: use FilterRecordByMaskColumn for pre-built masks or
+// FilterRecordByPredicate to filter with a Go predicate.
+func FilterRecordByColumn(ctx context.Context, input arrow.Record, colName string, condition arrow.Array) (arrow.Record, error) {
+	return FilterRecordByMaskColumn(ctx, input, colName, condition)
+}
+
+// FilterRecordByPredicate returns a new record with only rows where the predicate
+// returns true for the named column. The predicate receives the column and the row
+// index; rows where the column is null are dropped without calling the predicate.
+func FilterRecordByPredicate(ctx context.Context, input arrow.Record, colName string, predicate func(col arrow.Array, i int) bool) (arrow.Record, error) {
+	// Get column by name
+	col, err := GetColumn(input, colName)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(col)
+
+	// Evaluate the predicate for each row
+	builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(col.Len())
+	for i := 0; i < col.Len(); i++ {
+		builder.Append(col.IsValid(i) && predicate(col, i))
+	}
+	mask := builder.NewArray()
+	defer mask.Release()
+
+	// Apply filtering
+	return FilterRecord(ctx, input, mask)
+}
+
+// EqualApprox returns a predicate for FilterRecordByPredicate or IndicesWhere
+// that matches numeric elements within tolerance of value, inclusive. Use it
+// for computed floats, where exact equality rarely holds; EqualScalar remains
+// the exact test for discrete types. Value must be a finite Go number and
+// tolerance must be non-negative, or an error is returned. Integer and float
+// columns, including Float16, are compared as float64; NaN elements and
+// elements of non-numeric columns never match.
+func EqualApprox(value interface{}, tolerance float64) (func(arr arrow.Array, i int) bool, error) {
+	target, ok := floatValue(value)
+	if !ok {
+		return nil, fmt.Errorf("approximate equality requires a numeric value, got %T", value)
+	}
+	if math.IsNaN(target) || math.IsInf(target, 0) {
+		return nil, fmt.Errorf("approximate equality requires a finite value, got %v", target)
+	}
+	if !(tolerance >= 0) {
+		return nil, fmt.Errorf("tolerance must be non-negative, got %v", tolerance)
+	}
+	return func(arr arrow.Array, i int) bool {
+		v, isNum := floatAt(arr, i)
+		return isNum && math.Abs(v-target) <= tolerance
+	}, nil
+}
+
+// floatAt returns element i of a numeric array as float64
+func floatAt(arr arrow.Array, i int) (float64, bool) {
+	switch a := arr.(type) {
+	case *array.Int8:
+		return float64(a.Value(i)), true
+	case *array.Int16:
+		return float64(a.Value(i)), true
+	case *array.Int32:
+		return float64(a.Value(i)), true
+	case *array.Int64:
+		return float64(a.Value(i)), true
+	case *array.Uint8:
+		return float64(a.Value(i)), true
+	case *array.Uint16:
+		return float64(a.Value(i)), true
+	case *array.Uint32:
+		return float64(a.Value(i)), true
+	case *array.Uint64:
+		return float64(a.Value(i)), true
+	case *array.Float16:
+		return float64(a.Value(i).Float32()), true
+	case *array.Float32:
+		return float64(a.Value(i)), true
+	case *array.Float64:
+		return a.Value(i), true
+	}
+	return 0, false
+}
+
+// FilterRecordByTriStatePredicate returns a new record filtered by a three-valued
+// predicate on the named column. The predicate is called for every row, including
+// nulls, and returns true to keep the row, false to drop it, or nil when the result
+// is unknown, in which case the row is kept. This allows null rows to be retained
+// while filtering on the non-null values.
+func FilterRecordByTriStatePredicate(ctx context.Context, input arrow.Record, colName string, predicate func(col arrow.Array, i int) *bool) (arrow.Record, error) {
+	// Get column by name
+	col, err := GetColumn(input, colName)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(col)
+
+	// Evaluate the predicate for each row, keeping unknown results
+	builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(col.Len())
+	for i := 0; i < col.Len(); i++ {
+		keep := predicate(col, i)
+		builder.Append(keep == nil || *keep)
+	}
+	mask := builder.NewArray()
+	defer mask.Release()
+
+	// Apply filtering
+	return FilterRecord(ctx, input, mask)
+}
+
 // FilterRecordByColumnValue returns a new record with only rows where the column equals the given value
 func FilterRecordByColumnValue(ctx context.Context, input arrow.Record, colName string, val interface{}) (arrow.Record, error) {
 	// Get column by name
@@ -283,3 +781,122 @@ func FilterRecordByColumnRange(ctx context.Context, input arrow.Record, colName
 	// Apply filtering
 	return FilterRecord(ctx, input, combinedMask)
 }
+
+// FilterRecordWhere returns a new record with only rows where comparing the
+// column to the given value with op is true
+func FilterRecordWhere(ctx context.Context, input arrow.Record, colName string, op CompareOp, val interface{}) (arrow.Record, error) {
+	// Get column by name
+	col, err := GetColumn(input, colName)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(col)
+
+	// Create mask for filtering
+	mask, err := CompareScalar(ctx, col, op, val)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(mask)
+
+	// Apply filtering
+	return FilterRecord(ctx, input, mask)
+}
+
+// FilterRecordAbovePercentile returns a new record with only rows where the
+// column is greater than or equal to its pct-th percentile, with pct a
+// percentage in [0, 100]
+func FilterRecordAbovePercentile(ctx context.Context, input arrow.Record, colName string, pct float64) (arrow.Record, error) {
+	return filterRecordByPercentile(ctx, input, colName, pct, GE)
+}
+
+// FilterRecordBelowPercentile returns a new record with only rows where the
+// column is less than or equal to its pct-th percentile, with pct a percentage
+// in [0, 100]
+func FilterRecordBelowPercentile(ctx context.Context, input arrow.Record, colName string, pct float64) (arrow.Record, error) {
+	return filterRecordByPercentile(ctx, input, colName, pct, LE)
+}
+
+// filterRecordByPercentile filters rows by comparing a column against its pct-th percentile
+func filterRecordByPercentile(ctx context.Context, input arrow.Record, colName string, pct float64, op CompareOp) (arrow.Record, error) {
+	// Get column by name
+	col, err := GetColumn(input, colName)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(col)
+
+	// Create mask for filtering
+	mask, err := percentileMask(ctx, col, pct, op)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(mask)
+
+	// Apply filtering
+	return FilterRecord(ctx, input, mask)
+}
+
+// CompareColumns returns a mask array comparing two columns of the record row-wise with op.
+// Options, if given, are passed to the comparison kernel.
+func CompareColumns(ctx context.Context, rec arrow.Record, colA string, op CompareOp, colB string, opts ...compute.FunctionOptions) (arrow.Array, error) {
+	funcName, err := op.funcName()
+	if err != nil {
+		return nil, err
+	}
+	fnOpts, err := functionOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get columns by name
+	a, err := GetColumn(rec, colA)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(a)
+
+	b, err := GetColumn(rec, colB)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(b)
+
+	result, err := compute.CallFunction(ctx, funcName, fnOpts, compute.NewDatum(a), compute.NewDatum(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", funcName, err)
+	}
+	defer result.Release()
+
+	return result.(*compute.ArrayDatum).MakeArray(), nil
+}
+
+// EqualColumns returns a mask array indicating which rows have equal values in both columns
+func EqualColumns(ctx context.Context, rec arrow.Record, colA, colB string) (arrow.Array, error) {
+	return CompareColumns(ctx, rec, colA, EQ, colB)
+}
+
+// NotEqualColumns returns a mask array indicating which rows have different values in the columns
+func NotEqualColumns(ctx context.Context, rec arrow.Record, colA, colB string) (arrow.Array, error) {
+	return CompareColumns(ctx, rec, colA, NE, colB)
+}
+
+// GreaterColumns returns a mask array indicating which rows have colA greater than colB
+func GreaterColumns(ctx context.Context, rec arrow.Record, colA, colB string) (arrow.Array, error) {
+	return CompareColumns(ctx, rec, colA, GT, colB)
+}
+
+// GreaterEqualColumns returns a mask array indicating which rows have colA greater than or equal to colB
+func GreaterEqualColumns(ctx context.Context, rec arrow.Record, colA, colB string) (arrow.Array, error) {
+	return CompareColumns(ctx, rec, colA, GE, colB)
+}
+
+// LessColumns returns a mask array indicating which rows have colA less than colB
+func LessColumns(ctx context.Context, rec arrow.Record, colA, colB string) (arrow.Array, error) {
+	return CompareColumns(ctx, rec, colA, LT, colB)
+}
+
+// LessEqualColumns returns a mask array indicating which rows have colA less than or equal to colB
+func LessEqualColumns(ctx context.Context, rec arrow.Record, colA, colB string) (arrow.Array, error) {
+	return CompareColumns(ctx, rec, colA, LE, colB)
+}
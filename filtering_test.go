@@ -3,11 +3,16 @@ package archery_test
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/TFMV/archery"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/bitutil"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/compute/exec"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
 )
 
 func Example_filter() {
@@ -222,3 +227,687 @@ func Example_nullFiltering() {
 	// 1 3 5 6
 	// Null count: 2
 }
+
+func Example_filterRecordByPredicate() {
+	// Create a test record
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"alice", "bob", "carol", "dave"}, nil)
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	ageBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer ageBuilder.Release()
+	ageBuilder.AppendValues([]int64{34, 27, 0, 45}, []bool{true, true, false, true})
+	ages := ageBuilder.NewArray()
+	defer ages.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "age", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{names, ages}, 4)
+	defer rec.Release()
+
+	// Keep rows where age is over 30
+	ctx := context.Background()
+	filtered, err := archery.FilterRecordByPredicate(ctx, rec, "age", func(col arrow.Array, i int) bool {
+		return col.(*array.Int64).Value(i) > 30
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(filtered)
+
+	// Print the remaining names
+	fmt.Println("Older than 30:")
+	filteredNames := filtered.Column(0).(*array.String)
+	for i := 0; i < filteredNames.Len(); i++ {
+		if i > 0 {
+			fmt.Printf(" ")
+		}
+		fmt.Print(filteredNames.Value(i))
+	}
+	fmt.Println()
+
+	// Output:
+	// Older than 30:
+	// alice dave
+}
+
+func Example_compareScalars() {
+	// Create a test array
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{5, 20, 50, 150}, nil)
+	arr := builder.NewInt64Array()
+	defer arr.Release()
+
+	// Build several masks over the same array
+	ctx := context.Background()
+	ops := []archery.CompareOp{archery.GT, archery.LT, archery.EQ}
+	masks, err := archery.CompareScalars(ctx, arr, ops, []interface{}{10, 100, 50})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer func() {
+		for _, mask := range masks {
+			mask.Release()
+		}
+	}()
+
+	// Print each mask
+	for _, mask := range masks {
+		fmt.Println(mask)
+	}
+
+	// Output:
+	// [false true true true]
+	// [true true true false]
+	// [false false true false]
+}
+
+func Example_where() {
+	// Create a test array
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{-3, 5, -1, 8}, nil)
+	arr := builder.NewInt64Array()
+	defer arr.Release()
+
+	// Replace negative scores with zero
+	ctx := context.Background()
+	positive, err := archery.GreaterScalar(ctx, arr, int64(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(positive)
+
+	clamped, err := archery.WhereScalar(ctx, positive, arr, int64(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(clamped)
+
+	// Print the result
+	fmt.Println(clamped)
+
+	// Keep the negative scores instead, using the inverted mask
+	negative, err := archery.Invert(ctx, positive)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(negative)
+	fmt.Println(negative)
+
+	negatives, err := archery.Where(ctx, negative, arr, clamped)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(negatives)
+	fmt.Println(negatives)
+
+	// A cancelled context is reported rather than hidden by the fallback
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = archery.Where(cancelled, negative, arr, clamped)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// [0 5 0 8]
+	// [true false true false]
+	// [-3 5 -1 8]
+	// Error: context canceled
+}
+
+func Example_caseWhen() {
+	// Create a test array of scores
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{95, 72, 40, 88}, nil)
+	scores := builder.NewInt64Array()
+	defer scores.Release()
+
+	// Create the label arrays for each bucket
+	labels := func(label string) arrow.Array {
+		b := array.NewStringBuilder(memory.DefaultAllocator)
+		defer b.Release()
+		for i := 0; i < scores.Len(); i++ {
+			b.Append(label)
+		}
+		return b.NewArray()
+	}
+	high, mid, low := labels("high"), labels("mid"), labels("low")
+	defer high.Release()
+	defer mid.Release()
+	defer low.Release()
+
+	// Build the conditions
+	ctx := context.Background()
+	isHigh, err := archery.GreaterEqualScalar(ctx, scores, int64(90))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(isHigh)
+
+	isMid, err := archery.GreaterEqualScalar(ctx, scores, int64(70))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(isMid)
+
+	// Assign each score to the first matching bucket
+	buckets, err := archery.CaseWhen(ctx, []arrow.Array{isHigh, isMid}, []arrow.Array{high, mid}, low)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(buckets)
+
+	// Print the result
+	fmt.Println(buckets)
+
+	// Output:
+	// ["high" "mid" "low" "mid"]
+}
+
+func Example_filterRecordWhere() {
+	// Create a test record
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{1, 2, 3, 4}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	ageBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer ageBuilder.Release()
+	ageBuilder.AppendValues([]int64{25, 31, 47, 30}, nil)
+	ages := ageBuilder.NewArray()
+	defer ages.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "age", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids, ages}, 4)
+	defer rec.Release()
+
+	// Keep rows where age > 30
+	ctx := context.Background()
+	filtered, err := archery.FilterRecordWhere(ctx, rec, "age", archery.GT, 30)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(filtered)
+
+	// Print the remaining ids
+	fmt.Println("IDs:", filtered.Column(0))
+
+	// Output:
+	// IDs: [2 3]
+}
+
+func Example_filterRecordByTriStatePredicate() {
+	// Create a test record with a nullable column
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{5, 0, 12, 8, 20}, []bool{true, false, true, true, true})
+	values := builder.NewArray()
+	defer values.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{values}, 5)
+	defer rec.Release()
+
+	// Keep values above 7 along with the null rows
+	ctx := context.Background()
+	filtered, err := archery.FilterRecordByTriStatePredicate(ctx, rec, "value", func(col arrow.Array, i int) *bool {
+		if col.IsNull(i) {
+			return nil
+		}
+		keep := col.(*array.Int64).Value(i) > 7
+		return &keep
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(filtered)
+
+	// Print the remaining values
+	fmt.Println(filtered.Column(0))
+
+	// Output:
+	// [(null) 12 8 20]
+}
+
+func Example_greaterColumns() {
+	// Create a test record
+	actualBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer actualBuilder.Release()
+	actualBuilder.AppendValues([]float64{10, 20, 30}, nil)
+	actual := actualBuilder.NewArray()
+	defer actual.Release()
+
+	forecastBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer forecastBuilder.Release()
+	forecastBuilder.AppendValues([]float64{12, 18, 30}, nil)
+	forecast := forecastBuilder.NewArray()
+	defer forecast.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "actual", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "forecast", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{actual, forecast}, 3)
+	defer rec.Release()
+
+	// Find rows where actual beat the forecast
+	ctx := context.Background()
+	mask, err := archery.GreaterColumns(ctx, rec, "actual", "forecast")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(mask)
+
+	// Print the mask
+	fmt.Println(mask)
+
+	// Output:
+	// [false true false]
+}
+
+func Example_equalNullSafe() {
+	// Create two arrays with nulls
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 0, 0, 4}, []bool{true, false, false, true})
+	a := builder.NewArray()
+	defer a.Release()
+	builder.AppendValues([]int64{1, 0, 3, 5}, []bool{true, false, true, true})
+	b := builder.NewArray()
+	defer b.Release()
+
+	// Compare with regular and null-safe equality
+	ctx := context.Background()
+	eq, err := archery.Equal(ctx, a, b)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(eq)
+
+	nullSafe, err := archery.EqualNullSafe(ctx, a, b)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(nullSafe)
+
+	// Print both masks
+	fmt.Println("Equal:", eq)
+	fmt.Println("Null-safe:", nullSafe)
+
+	// Output:
+	// Equal: [true (null) (null) false]
+	// Null-safe: [true true false false]
+}
+
+func Example_filterAbovePercentile() {
+	// Create a test record
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	scoreBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]int64{40, 95, 10, 70, 85, 20, 60, 30, 50, 80}, nil)
+	scores := scoreBuilder.NewArray()
+	defer scores.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "score", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids, scores}, 10)
+	defer rec.Release()
+
+	// Keep the top 20% of scores
+	ctx := context.Background()
+	top, err := archery.FilterAbovePercentile(ctx, scores, 80)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(top)
+
+	// Keep the rows with the bottom 30% of scores
+	bottom, err := archery.FilterRecordBelowPercentile(ctx, rec, "score", 30)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(bottom)
+
+	// Print the results
+	fmt.Println("Top scores:", top)
+	fmt.Println("Bottom IDs:", bottom.Column(0))
+
+	// Output:
+	// Top scores: [95 85]
+	// Bottom IDs: [3 6 8]
+}
+
+func Example_popCount() {
+	// Create a mask with a null
+	builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]bool{true, false, true, true, true}, []bool{true, true, false, true, true})
+	mask := builder.NewBooleanArray()
+	defer mask.Release()
+
+	// Count the rows the mask selects, including on a slice
+	sliced := array.NewSlice(mask, 1, 5).(*array.Boolean)
+	defer sliced.Release()
+
+	fmt.Println("Selected:", archery.PopCount(mask))
+	fmt.Println("Selected in slice:", archery.PopCount(sliced))
+
+	// Output:
+	// Selected: 3
+	// Selected in slice: 2
+}
+
+func Example_indicesToMask() {
+	// Row indices picked by an external process, with a repeat
+	mask, err := archery.IndicesToMask([]int64{3, 0, 3}, 5, nil)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer mask.Release()
+
+	fmt.Println("Mask:", mask)
+	fmt.Println("Selected:", archery.PopCount(mask))
+
+	// Indices must fall inside the mask
+	_, err = archery.IndicesToMask([]int64{5}, 5, nil)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Mask: [true false false true false]
+	// Selected: 2
+	// Error: index 5 out of range (0-4)
+}
+
+func Example_indicesWhere() {
+	ctx := context.Background()
+	pool := memory.NewGoAllocator()
+
+	// A flag column and a parallel array of values
+	flagBuilder := array.NewBooleanBuilder(pool)
+	defer flagBuilder.Release()
+	flagBuilder.AppendValues([]bool{true, false, true, false}, []bool{true, true, true, false})
+	flags := flagBuilder.NewArray()
+	defer flags.Release()
+
+	valBuilder := array.NewStringBuilder(pool)
+	defer valBuilder.Release()
+	valBuilder.AppendValues([]string{"a", "b", "c", "d"}, nil)
+	values := valBuilder.NewArray()
+	defer values.Release()
+
+	// Find where the flag is set; the null flag is skipped
+	indices, err := archery.IndicesWhere(ctx, flags, func(arr arrow.Array, i int) bool {
+		return arr.(*array.Boolean).Value(i)
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Indices:", indices)
+
+	// Gather the same positions from the parallel array
+	idxBuilder := array.NewInt64Builder(pool)
+	defer idxBuilder.Release()
+	idxBuilder.AppendValues(indices, nil)
+	idxArr := idxBuilder.NewArray()
+	defer idxArr.Release()
+
+	picked, err := archery.TakeWithIndices(ctx, values, idxArr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer picked.Release()
+	fmt.Println("Picked:", picked)
+
+	// Output:
+	// Indices: [0 2]
+	// Picked: ["a" "c"]
+}
+
+func Example_equalApprox() {
+	// Ratios computed in floating point rarely hit 0.5 exactly
+	ratioBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer ratioBuilder.Release()
+	ratioBuilder.AppendValues([]float64{0.7 - 0.2, 1.0 / 3.0, 0.49999999999, 0.7}, nil)
+	ratios := ratioBuilder.NewArray()
+	defer ratios.Release()
+
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{1, 2, 3, 4}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "ratio", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids, ratios}, 4)
+	defer rec.Release()
+
+	// Keep rows where the ratio is 0.5 within a small tolerance
+	nearHalf, err := archery.EqualApprox(0.5, 1e-9)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	ctx := context.Background()
+	filtered, err := archery.FilterRecordByPredicate(ctx, rec, "ratio", nearHalf)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(filtered)
+	fmt.Println("IDs:", filtered.Column(0))
+
+	// Predicates that could never match are rejected when built
+	_, err = archery.EqualApprox("0.5", 1e-9)
+	fmt.Println("Error:", err)
+	_, err = archery.EqualApprox(math.NaN(), 1e-9)
+	fmt.Println("Error:", err)
+	_, err = archery.EqualApprox(0.5, -1e-9)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// IDs: [1 3]
+	// Error: approximate equality requires a numeric value, got string
+	// Error: approximate equality requires a finite value, got NaN
+	// Error: tolerance must be non-negative, got -1e-09
+}
+
+// toleranceOptions is a custom compute.FunctionOptions read by the "equal"
+// kernel registered by toleranceContext
+type toleranceOptions struct {
+	Tolerance int64
+}
+
+func (toleranceOptions) TypeName() string { return "ToleranceOptions" }
+
+// toleranceContext returns a context whose registry has an int64 "equal"
+// kernel that honours a tolerance when options are given
+func toleranceContext() (context.Context, error) {
+	equal := compute.NewScalarFunction("equal", compute.Binary(), compute.EmptyFuncDoc)
+	initFn := func(_ *exec.KernelCtx, args exec.KernelInitArgs) (exec.KernelState, error) {
+		return args.Options, nil
+	}
+	execFn := func(kctx *exec.KernelCtx, batch *exec.ExecSpan, out *exec.ExecResult) error {
+		var tolerance int64
+		if opts, ok := kctx.State.(*toleranceOptions); ok {
+			tolerance = opts.Tolerance
+		}
+		values := exec.GetSpanValues[int64](&batch.Values[0].Array, 1)
+		for i, v := range values {
+			var target int64
+			if batch.Values[1].IsScalar() {
+				target = batch.Values[1].Scalar.(*scalar.Int64).Value
+			} else {
+				target = exec.GetSpanValues[int64](&batch.Values[1].Array, 1)[i]
+			}
+			diff := v - target
+			bitutil.SetBitTo(out.Buffers[1].Buf, int(out.Offset)+i, diff >= -tolerance && diff <= tolerance)
+		}
+		return nil
+	}
+	inputs := []exec.InputType{
+		exec.NewExactInput(arrow.PrimitiveTypes.Int64),
+		exec.NewExactInput(arrow.PrimitiveTypes.Int64),
+	}
+	if err := equal.AddNewKernel(inputs, exec.NewOutputType(arrow.FixedWidthTypes.Boolean), execFn, initFn); err != nil {
+		return nil, err
+	}
+	registry := compute.NewChildRegistry(compute.GetFunctionRegistry())
+	registry.AddFunction(equal, true)
+
+	execCtx := compute.DefaultExecCtx()
+	execCtx.Registry = registry
+	return compute.SetExecCtx(context.Background(), execCtx), nil
+}
+
+func Example_equalScalarOptions() {
+	// Use an "equal" kernel that honours a tolerance when options are given
+	ctx, err := toleranceContext()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Create a test array
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{8, 10, 11, 13}, nil)
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Without options the kernel compares exactly; with them it sees the tolerance
+	exact, err := archery.EqualScalar(ctx, arr, int64(10))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer exact.Release()
+
+	near, err := archery.EqualScalar(ctx, arr, int64(10), &toleranceOptions{Tolerance: 1})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer near.Release()
+
+	// More than one options value is rejected
+	_, err = archery.EqualScalar(ctx, arr, int64(10), &toleranceOptions{}, &toleranceOptions{})
+
+	fmt.Println("Exact:", exact)
+	fmt.Println("Within 1:", near)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Exact: [false true false false]
+	// Within 1: [false true true false]
+	// Error: at most one options value may be given, got 2
+}
+
+func Example_compareOptions() {
+	// Use an "equal" kernel that honours a tolerance when options are given
+	ctx, err := toleranceContext()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Create a test record
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "b", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{8, 10, 11, 13}, nil)
+	builder.Field(1).(*array.Int64Builder).AppendValues([]int64{9, 10, 14, 11}, nil)
+	rec := builder.NewRecord()
+	defer rec.Release()
+	arr := rec.Column(0)
+
+	// The options reach every comparison made by CompareScalars
+	ops := []archery.CompareOp{archery.EQ, archery.EQ}
+	masks, err := archery.CompareScalars(ctx, arr, ops, []interface{}{10, 12}, &toleranceOptions{Tolerance: 1})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer func() {
+		for _, mask := range masks {
+			mask.Release()
+		}
+	}()
+
+	// And the row-wise comparison made by CompareColumns
+	exact, err := archery.CompareColumns(ctx, rec, "a", archery.EQ, "b")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer exact.Release()
+
+	near, err := archery.CompareColumns(ctx, rec, "a", archery.EQ, "b", &toleranceOptions{Tolerance: 2})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer near.Release()
+
+	// More than one options value is rejected by both
+	_, scalarsErr := archery.CompareScalars(ctx, arr, ops, []interface{}{10, 12}, &toleranceOptions{}, &toleranceOptions{})
+	_, columnsErr := archery.CompareColumns(ctx, rec, "a", archery.EQ, "b", &toleranceOptions{}, &toleranceOptions{})
+
+	fmt.Println("Within 1 of 10:", masks[0])
+	fmt.Println("Within 1 of 12:", masks[1])
+	fmt.Println("Columns exact:", exact)
+	fmt.Println("Columns within 2:", near)
+	fmt.Println("Error:", scalarsErr)
+	fmt.Println("Error:", columnsErr)
+
+	// Output:
+	// Within 1 of 10: [false true true false]
+	// Within 1 of 12: [false false true true]
+	// Columns exact: [false true false false]
+	// Columns within 2: [true true false true]
+	// Error: at most one options value may be given, got 2
+	// Error: at most one options value may be given, got 2
+}
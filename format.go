@@ -0,0 +1,91 @@
+package archery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// FORMATTING
+
+// FormatOptions controls how FormatRecord renders values. Zero fields take
+// their defaults, so a partially filled value only changes what it sets.
+type FormatOptions struct {
+	// NullString is printed in place of null values. Empty means "null"
+	// unless NullSet is true.
+	NullString string
+	// NullSet makes NullString apply even when empty, so nulls can render as
+	// blank cells
+	NullSet bool
+	// FloatPrecision is the number of decimal places for floating point values.
+	// Zero means the shortest representation that round-trips; use NoDecimals
+	// to round to whole numbers.
+	FloatPrecision int
+}
+
+// NoDecimals is the FloatPrecision that renders floats without decimal places
+const NoDecimals = -1
+
+// DefaultFormatOptions returns the options used by FormatRecord when none are
+// given: nulls render as "null" and floats use their shortest representation
+func DefaultFormatOptions() FormatOptions {
+	return FormatOptions{NullString: "null", NullSet: true}
+}
+
+// FormatRecord renders a record as an aligned text table with a header row of
+// column names. At most one FormatOptions value may be given; without one the
+// DefaultFormatOptions are used.
+func FormatRecord(rec arrow.Record, opts ...FormatOptions) (string, error) {
+	if len(opts) > 1 {
+		return "", fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	options := DefaultFormatOptions()
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(ColumnNames(rec), "\t"))
+
+	cells := make([]string, rec.NumCols())
+	for row := 0; row < int(rec.NumRows()); row++ {
+		for i, col := range rec.Columns() {
+			cells[i] = FormatValue(col, row, options)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	w.Flush()
+	return sb.String(), nil
+}
+
+// FormatValue renders the value at index i of the array using the given options
+func FormatValue(arr arrow.Array, i int, opts FormatOptions) string {
+	// strconv takes -1 for the shortest representation and 0 for no decimals
+	precision := opts.FloatPrecision
+	switch precision {
+	case 0:
+		precision = -1
+	case NoDecimals:
+		precision = 0
+	}
+
+	switch v := ValueAt(arr, i).(type) {
+	case nil:
+		if opts.NullString == "" && !opts.NullSet {
+			return DefaultFormatOptions().NullString
+		}
+		return opts.NullString
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', precision, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
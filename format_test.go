@@ -0,0 +1,98 @@
+package archery_test
+
+import (
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_formatRecord() {
+	// Create a test record with nulls
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"alice", "bob"}, nil)
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	scoreBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]float64{9.26, 0}, []bool{true, false})
+	scores := scoreBuilder.NewArray()
+	defer scores.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{names, scores}, 2)
+	defer rec.Release()
+
+	// Format with the defaults and with custom options
+	table, err := archery.FormatRecord(rec)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(table)
+
+	table, err = archery.FormatRecord(rec, archery.FormatOptions{NullString: "NA", FloatPrecision: 1})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(table)
+
+	// Output:
+	// name   score
+	// alice  9.26
+	// bob    null
+	// name   score
+	// alice  9.3
+	// bob    NA
+}
+
+func Example_formatRecordPartialOptions() {
+	// Create a test record with a null
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1.5, 0}, []bool{true, false})
+	prices := builder.NewArray()
+	defer prices.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{prices}, 2)
+	defer rec.Release()
+
+	// Unset fields keep their defaults: the first changes only the null
+	// marker, the next two only the precision, and NoDecimals rounds to whole
+	// numbers. NullSet renders nulls as blank cells.
+	for _, opts := range []archery.FormatOptions{
+		{NullString: "NA"},
+		{FloatPrecision: 2},
+		{FloatPrecision: archery.NoDecimals},
+		{NullSet: true},
+	} {
+		table, err := archery.FormatRecord(rec, opts)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("%q\n", table)
+	}
+
+	// More than one options value is an error
+	_, err := archery.FormatRecord(rec, archery.FormatOptions{}, archery.FormatOptions{})
+	fmt.Println("Error:", err)
+
+	// Output:
+	// "price\n1.5\nNA\n"
+	// "price\n1.50\nnull\n"
+	// "price\n2\nnull\n"
+	// "price\n1.5\n\n"
+	// Error: at most one options value may be given, got 2
+}
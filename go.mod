@@ -2,7 +2,10 @@ module github.com/TFMV/archery
 
 go 1.24.0
 
-require github.com/apache/arrow-go/v18 v18.3.0
+require (
+	github.com/apache/arrow-go/v18 v18.3.0
+	github.com/zeebo/xxh3 v1.0.2
+)
 
 require (
 	github.com/andybalholm/brotli v1.1.1 // indirect
@@ -17,7 +20,6 @@ require (
 	github.com/minio/asm2plan9s v0.0.0-20200509001527-cdd76441f9d8 // indirect
 	github.com/minio/c2goasm v0.0.0-20190812172519-36a3d3bbc4f3 // indirect
 	github.com/pierrec/lz4/v4 v4.1.22 // indirect
-	github.com/zeebo/xxh3 v1.0.2 // indirect
 	golang.org/x/exp v0.0.0-20250606033433-dcc06ee1d476 // indirect
 	golang.org/x/mod v0.25.0 // indirect
 	golang.org/x/sync v0.15.0 // indirect
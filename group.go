@@ -0,0 +1,668 @@
+package archery
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// GROUPING OPERATIONS
+
+// SplitRecordByColumn returns one sub-record per distinct non-null value of
+// the key column, keyed by the value's string form (arrow.Array.ValueStr), and
+// the rows with a null key as a separate record, or nil when there are none.
+// Keeping nulls apart means a string value equal to array.NullValueStr never
+// merges with them. Each sub-record keeps the input's schema and row order.
+//
+// Every group is materialized as its own record, so a high-cardinality key
+// produces many small records whose combined size matches the input. The
+// caller is responsible for releasing every returned record.
+func SplitRecordByColumn(ctx context.Context, input arrow.Record, keyCol string) (groups map[string]arrow.Record, nulls arrow.Record, err error) {
+	col, err := GetColumn(input, keyCol)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer ReleaseArray(col)
+
+	keys, rows, nullRows := groupIndices(col)
+
+	groups = make(map[string]arrow.Record, len(keys))
+	for _, key := range keys {
+		sub, err := takeRecordRows(ctx, input, rows[key])
+		if err != nil {
+			for _, rec := range groups {
+				rec.Release()
+			}
+			return nil, nil, fmt.Errorf("error splitting group %s: %w", key, err)
+		}
+		groups[key] = sub
+	}
+
+	if len(nullRows) > 0 {
+		nulls, err = takeRecordRows(ctx, input, nullRows)
+		if err != nil {
+			for _, rec := range groups {
+				rec.Release()
+			}
+			return nil, nil, fmt.Errorf("error splitting null group: %w", err)
+		}
+	}
+	return groups, nulls, nil
+}
+
+// TopNPerGroup returns up to n rows from each group of rows sharing the same
+// values in groupCols, keeping the rows that rank first by orderCol in the
+// given order. Each group is tracked with a bounded heap of size n, so the
+// input is scanned once without sorting whole groups. Groups with fewer than n
+// rows keep all of them.
+//
+// Groups appear in order of first appearance and rows within a group in rank
+// order. Nulls in the order column rank last and ties keep the earlier row.
+// With no group columns the whole record is one group.
+func TopNPerGroup(ctx context.Context, input arrow.Record, groupCols []string, orderCol string, n int, order SortOrder) (arrow.Record, error) {
+	rows, err := topNPerGroupRows(ctx, input, groupCols, orderCol, n, order)
+	if err != nil {
+		return nil, err
+	}
+	return takeRecordRows(ctx, input, rows)
+}
+
+// ArgMaxPerGroup returns, for each group of rows sharing the same values in
+// groupCols, the index of the row with the largest orderCol value, as an
+// Int64 array ready for TakeRecord. This answers "the latest row per key"
+// queries while keeping whole rows reachable. Groups appear in order of first
+// appearance, ties keep the earlier row, and nulls rank last, so a group picks
+// a null-ordered row only when all of its values are null.
+func ArgMaxPerGroup(ctx context.Context, input arrow.Record, groupCols []string, orderCol string) (arrow.Array, error) {
+	return argExtremePerGroup(ctx, input, groupCols, orderCol, Descending)
+}
+
+// ArgMinPerGroup is ArgMaxPerGroup for the smallest orderCol value
+func ArgMinPerGroup(ctx context.Context, input arrow.Record, groupCols []string, orderCol string) (arrow.Array, error) {
+	return argExtremePerGroup(ctx, input, groupCols, orderCol, Ascending)
+}
+
+// argExtremePerGroup returns the index of the first ranked row of each group
+func argExtremePerGroup(ctx context.Context, input arrow.Record, groupCols []string, orderCol string, order SortOrder) (arrow.Array, error) {
+	rows, err := topNPerGroupRows(ctx, input, groupCols, orderCol, 1, order)
+	if err != nil {
+		return nil, err
+	}
+
+	mem, _ := contextAllocator(ctx)
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.AppendValues(rows, nil)
+	return builder.NewArray(), nil
+}
+
+// topNPerGroupRows returns the row indices selected by TopNPerGroup
+func topNPerGroupRows(ctx context.Context, input arrow.Record, groupCols []string, orderCol string, n int, order SortOrder) ([]int64, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("n must be positive, got %d", n)
+	}
+
+	keyCols := make([]arrow.Array, len(groupCols))
+	for i, name := range groupCols {
+		idx, err := GetColumnIndex(input, name)
+		if err != nil {
+			return nil, err
+		}
+		keyCols[i] = input.Column(idx)
+	}
+
+	orderIdx, err := GetColumnIndex(input, orderCol)
+	if err != nil {
+		return nil, err
+	}
+	orderArr := input.Column(orderIdx)
+	compare, err := valueComparator(orderArr, order)
+	if err != nil {
+		return nil, err
+	}
+
+	// before reports whether row a ranks ahead of row b
+	before := func(a, b int64) bool {
+		nullA, nullB := orderArr.IsNull(int(a)), orderArr.IsNull(int(b))
+		if nullA != nullB {
+			return nullB
+		}
+		if c := compare(a, b); c != 0 {
+			return c < 0
+		}
+		return a < b
+	}
+
+	var keys []string
+	heaps := make(map[string]*rankHeap)
+	for row := 0; row < int(input.NumRows()); row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		key := compositeKey(keyCols, row)
+		h, ok := heaps[key]
+		if !ok {
+			h = &rankHeap{before: before}
+			heaps[key] = h
+			keys = append(keys, key)
+		}
+
+		// The heap root is the lowest ranked row kept so far
+		switch {
+		case h.Len() < n:
+			heap.Push(h, int64(row))
+		case before(int64(row), h.rows[0]):
+			h.rows[0] = int64(row)
+			heap.Fix(h, 0)
+		}
+	}
+
+	var rows []int64
+	for _, key := range keys {
+		kept := heaps[key].rows
+		sort.Slice(kept, func(i, j int) bool {
+			return before(kept[i], kept[j])
+		})
+		rows = append(rows, kept...)
+	}
+	return rows, nil
+}
+
+// rankHeap is a heap of row indices with the lowest ranked row at the root
+type rankHeap struct {
+	rows   []int64
+	before func(a, b int64) bool
+}
+
+func (h *rankHeap) Len() int           { return len(h.rows) }
+func (h *rankHeap) Less(i, j int) bool { return h.before(h.rows[j], h.rows[i]) }
+func (h *rankHeap) Swap(i, j int)      { h.rows[i], h.rows[j] = h.rows[j], h.rows[i] }
+func (h *rankHeap) Push(x interface{}) { h.rows = append(h.rows, x.(int64)) }
+func (h *rankHeap) Pop() interface{} {
+	last := h.rows[len(h.rows)-1]
+	h.rows = h.rows[:len(h.rows)-1]
+	return last
+}
+
+// GroupOptions controls how GroupByAuto, GroupTransform and
+// WeightedMeanPerGroup form and aggregate groups
+type GroupOptions struct {
+	// DropNullKeys excludes rows with a null in any key column from grouping,
+	// as SQL GROUP BY does in many dialects. By default null keys form a group
+	// of their own.
+	DropNullKeys bool
+	// Parallelism is the most groups GroupByAuto and GroupTransform aggregate
+	// at once, capped at runtime.GOMAXPROCS. Zero or one aggregates serially.
+	// Results and errors match the serial path, but aggregators must be safe
+	// for concurrent use.
+	Parallelism int
+}
+
+// groupOptions returns the single options value of a variadic parameter, or
+// the zero value when none is given
+func groupOptions(opts []GroupOptions) (GroupOptions, error) {
+	if len(opts) > 1 {
+		return GroupOptions{}, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	if len(opts) == 1 {
+		return opts[0], nil
+	}
+	return GroupOptions{}, nil
+}
+
+// GroupTransform computes agg over colName for each group of rows sharing the
+// same values in keyCols and returns the record with the group's result
+// broadcast to each of its rows as a new column named colName + "_transform",
+// like pandas' groupby().transform(). Unlike aggregating, the rows are kept,
+// so each row can be compared with its group, e.g. its deviation from the
+// group mean. The new column takes the type of the aggregator's Go result and
+// groups with a nil result get nulls. With GroupOptions.DropNullKeys, rows
+// with a null key are kept but get a null.
+func GroupTransform(ctx context.Context, input arrow.Record, keyCols []string, colName string, agg Aggregator, opts ...GroupOptions) (arrow.Record, error) {
+	options, err := groupOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	groupOf, groupRows, err := groupRowsByKey(ctx, input, keyCols, options.DropNullKeys)
+	if err != nil {
+		return nil, err
+	}
+	colIdx, err := GetColumnIndex(input, colName)
+	if err != nil {
+		return nil, err
+	}
+
+	perGroup, err := aggregateGroups(ctx, input.Column(colIdx), colName, groupRows, agg, options.Parallelism)
+	if err != nil {
+		return nil, err
+	}
+	defer perGroup.Release()
+
+	// Scatter the group results back to the rows; dropped rows are -1
+	broadcast, err := takeArrayRows(ctx, perGroup, groupOf, TakeOptions{Negative: NegativeIndexNull})
+	if err != nil {
+		return nil, err
+	}
+	defer broadcast.Release()
+
+	field := arrow.Field{Name: colName + "_transform", Type: broadcast.DataType(), Nullable: true}
+	return AppendColumn(input, field, broadcast)
+}
+
+// GroupByAuto summarizes each group of rows sharing the same values in keyCols
+// as one row, in order of first appearance, as a quick "summarize by X" before
+// choosing explicit aggregators. The result holds the key columns, then every
+// other column aggregated by its entry in overrides or by default summed when
+// numeric or decimal and taken from the group's first row otherwise, then a
+// count column with the number of rows in each group. Null keys form a group
+// of their own unless GroupOptions.DropNullKeys is set. Aggregated columns take
+// the type of the aggregator's Go result, and groups with a nil result get
+// nulls.
+func GroupByAuto(ctx context.Context, input arrow.Record, keyCols []string, overrides map[string]Aggregator, opts ...GroupOptions) (arrow.Record, error) {
+	options, err := groupOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyCols) == 0 {
+		return nil, fmt.Errorf("at least one key column is required")
+	}
+	isKey := make(map[string]bool, len(keyCols))
+	for _, name := range keyCols {
+		isKey[name] = true
+	}
+	for name := range overrides {
+		if _, err := GetColumnIndex(input, name); err != nil {
+			return nil, fmt.Errorf("override: %w", err)
+		}
+		if isKey[name] {
+			return nil, fmt.Errorf("cannot override key column %s", name)
+		}
+	}
+	if !isKey["count"] && input.Schema().HasField("count") {
+		return nil, fmt.Errorf("column count collides with the group size column")
+	}
+
+	_, groupRows, err := groupRowsByKey(ctx, input, keyCols, options.DropNullKeys)
+	if err != nil {
+		return nil, err
+	}
+	firstRows := make([]int64, len(groupRows))
+	counts := make([]int64, len(groupRows))
+	for g, rows := range groupRows {
+		firstRows[g] = rows[0]
+		counts[g] = int64(len(rows))
+	}
+
+	fields := make([]arrow.Field, 0, input.NumCols()+1)
+	cols := make([]arrow.Array, 0, input.NumCols()+1)
+	defer func() {
+		ReleaseArrays(cols...)
+	}()
+
+	// Keys come first, in the order given
+	for _, name := range keyCols {
+		idx, err := GetColumnIndex(input, name)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := takeArrayRows(ctx, input.Column(idx), firstRows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, input.Schema().Field(idx))
+		cols = append(cols, keys)
+	}
+
+	for i, field := range input.Schema().Fields() {
+		if isKey[field.Name] {
+			continue
+		}
+		col := input.Column(i)
+
+		agg, ok := overrides[field.Name]
+		if !ok && (isNumericType(field.Type) || arrow.IsDecimal(field.Type.ID())) {
+			agg = Sum
+		}
+		if agg == nil {
+			first, err := takeArrayRows(ctx, col, firstRows)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+			cols = append(cols, first)
+			continue
+		}
+
+		result, err := aggregateGroups(ctx, col, field.Name, groupRows, agg, options.Parallelism)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, arrow.Field{Name: field.Name, Type: result.DataType(), Nullable: true})
+		cols = append(cols, result)
+	}
+
+	mem, _ := contextAllocator(ctx)
+	countBuilder := array.NewInt64Builder(mem)
+	defer countBuilder.Release()
+	countBuilder.AppendValues(counts, nil)
+	fields = append(fields, arrow.Field{Name: "count", Type: arrow.PrimitiveTypes.Int64})
+	cols = append(cols, countBuilder.NewArray())
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), cols, int64(len(groupRows))), nil
+}
+
+// WeightedMeanPerGroup returns one row per group of rows sharing the same
+// values in keyCols, in order of first appearance, holding the key columns and
+// the mean of valueCol weighted by weightCol as valueCol + "_weighted_mean",
+// e.g. the volume-weighted average price per symbol. Each group follows
+// WeightedMean, so a group whose weights sum to zero is an error. Null keys
+// form a group of their own unless GroupOptions.DropNullKeys is set.
+func WeightedMeanPerGroup(ctx context.Context, input arrow.Record, keyCols []string, valueCol, weightCol string, opts ...GroupOptions) (arrow.Record, error) {
+	options, err := groupOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyCols) == 0 {
+		return nil, fmt.Errorf("at least one key column is required")
+	}
+	_, groupRows, err := groupRowsByKey(ctx, input, keyCols, options.DropNullKeys)
+	if err != nil {
+		return nil, err
+	}
+	valueIdx, err := GetColumnIndex(input, valueCol)
+	if err != nil {
+		return nil, err
+	}
+	weightIdx, err := GetColumnIndex(input, weightCol)
+	if err != nil {
+		return nil, err
+	}
+
+	firstRows := make([]int64, len(groupRows))
+	mem, _ := contextAllocator(ctx)
+	meanBuilder := array.NewFloat64Builder(mem)
+	defer meanBuilder.Release()
+	meanBuilder.Reserve(len(groupRows))
+	for g, rows := range groupRows {
+		firstRows[g] = rows[0]
+		mean, err := weightedMeanRows(ctx, input.Column(valueIdx), input.Column(weightIdx), rows)
+		if err != nil {
+			return nil, fmt.Errorf("error aggregating group %d of column %s: %w", g, valueCol, err)
+		}
+		meanBuilder.Append(mean)
+	}
+
+	keyRec, err := SelectColumns(input, keyCols...)
+	if err != nil {
+		return nil, err
+	}
+	defer keyRec.Release()
+	keys, err := takeRecordRows(ctx, keyRec, firstRows)
+	if err != nil {
+		return nil, err
+	}
+	defer keys.Release()
+
+	means := meanBuilder.NewArray()
+	defer means.Release()
+	field := arrow.Field{Name: valueCol + "_weighted_mean", Type: arrow.PrimitiveTypes.Float64}
+	return AppendColumn(keys, field, means)
+}
+
+// weightedMeanRows returns the weighted mean of the given rows of values
+func weightedMeanRows(ctx context.Context, values, weights arrow.Array, rows []int64) (float64, error) {
+	groupValues, err := takeArrayRows(ctx, values, rows)
+	if err != nil {
+		return 0, err
+	}
+	defer groupValues.Release()
+	groupWeights, err := takeArrayRows(ctx, weights, rows)
+	if err != nil {
+		return 0, err
+	}
+	defer groupWeights.Release()
+	return WeightedMean(ctx, groupValues, groupWeights)
+}
+
+// groupRowsByKey numbers the groups of rows sharing the same values in keyCols
+// in order of first appearance. It returns each row's group and each group's
+// rows. With dropNullKeys, rows with a null in any key column belong to no
+// group and their group is -1.
+func groupRowsByKey(ctx context.Context, input arrow.Record, keyCols []string, dropNullKeys bool) (groupOf []int64, groupRows [][]int64, err error) {
+	keys := make([]arrow.Array, len(keyCols))
+	for i, name := range keyCols {
+		idx, err := GetColumnIndex(input, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[i] = input.Column(idx)
+	}
+
+	groupOf = make([]int64, input.NumRows())
+	groupByKey := make(map[string]int64)
+	for row := range groupOf {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		if dropNullKeys && anyNull(keys, row) {
+			groupOf[row] = -1
+			continue
+		}
+		key := compositeKey(keys, row)
+		g, ok := groupByKey[key]
+		if !ok {
+			g = int64(len(groupRows))
+			groupByKey[key] = g
+			groupRows = append(groupRows, nil)
+		}
+		groupRows[g] = append(groupRows[g], int64(row))
+		groupOf[row] = g
+	}
+	return groupOf, groupRows, nil
+}
+
+// aggregateGroups applies agg to the rows of col in each group and returns the
+// results as an array with one element per group. With parallelism above one,
+// groups are aggregated on up to that many goroutines; the first failing
+// group, in group order, decides the error or panic, as it would serially.
+func aggregateGroups(ctx context.Context, col arrow.Array, colName string, groupRows [][]int64, agg Aggregator, parallelism int) (arrow.Array, error) {
+	results := make([]interface{}, len(groupRows))
+	defer func() {
+		for _, result := range results {
+			releaseResult(result)
+		}
+	}()
+	errs := make([]error, len(groupRows))
+	workers := min(parallelism, runtime.GOMAXPROCS(0), len(groupRows))
+	if workers <= 1 {
+		for g, rows := range groupRows {
+			if results[g], errs[g] = aggregateRows(ctx, col, rows, agg); errs[g] != nil {
+				break
+			}
+		}
+	} else {
+		// Panics, such as a BoundedAllocator over its limit, are re-raised on
+		// this goroutine so SafeCall still recovers them
+		panics := make([]interface{}, len(groupRows))
+		parallelFor(len(groupRows), workers, func(g int) bool {
+			defer func() {
+				if r := recover(); r != nil {
+					panics[g] = r
+				}
+			}()
+			results[g], errs[g] = aggregateRows(ctx, col, groupRows[g], agg)
+			return errs[g] == nil
+		})
+		for g := range groupRows {
+			if panics[g] != nil {
+				panic(panics[g])
+			}
+			if errs[g] != nil {
+				break
+			}
+		}
+	}
+	for g, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("error aggregating group %d of column %s: %w", g, colName, err)
+		}
+	}
+
+	perGroup, err := groupResultArray(ctx, results, col.DataType())
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating column %s: %w", colName, err)
+	}
+	return perGroup, nil
+}
+
+// parallelFor calls fn for each i in [0, n) on the given number of
+// goroutines. Indices are handed out in increasing order, and none are handed
+// out after fn returns false, so every index below a failing one has run.
+func parallelFor(n, workers int, fn func(i int) bool) {
+	var next atomic.Int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				if !fn(i) {
+					stop.Store(true)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// aggregateRows applies agg to the given rows of col
+func aggregateRows(ctx context.Context, col arrow.Array, rows []int64, agg Aggregator) (interface{}, error) {
+	values, err := takeArrayRows(ctx, col, rows)
+	if err != nil {
+		return nil, err
+	}
+	defer values.Release()
+	return agg(ctx, values)
+}
+
+// groupResultArray returns an array with one element per group result. Its
+// type comes from the first non-nil result, and nil results are nulls.
+func groupResultArray(ctx context.Context, results []interface{}, inputType arrow.DataType) (arrow.Array, error) {
+	mem, _ := contextAllocator(ctx)
+	parts := make([]arrow.Array, len(results))
+	defer func() {
+		ReleaseArrays(parts...)
+	}()
+
+	var resultType arrow.DataType = arrow.Null
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		part, err := resultArray(mem, result, inputType)
+		if err != nil {
+			return nil, err
+		}
+		if resultType.ID() == arrow.NULL {
+			resultType = part.DataType()
+		} else if !arrow.TypeEqual(resultType, part.DataType()) {
+			part.Release()
+			return nil, fmt.Errorf("group results have different types: %s and %s", resultType, part.DataType())
+		}
+		parts[i] = part
+	}
+	for i := range parts {
+		if parts[i] == nil {
+			parts[i] = array.MakeArrayOfNull(mem, resultType, 1)
+		}
+	}
+	if len(parts) == 0 {
+		return array.MakeArrayOfNull(mem, resultType, 0), nil
+	}
+	return array.Concatenate(parts, mem)
+}
+
+// compositeKey returns a string identifying the values of the columns at row.
+// Each value is length-prefixed so that distinct tuples never share a key, and
+// nulls are tagged apart from any string form.
+func compositeKey(cols []arrow.Array, row int) string {
+	var sb strings.Builder
+	for _, col := range cols {
+		if col.IsNull(row) {
+			sb.WriteString("-;")
+			continue
+		}
+		str := col.ValueStr(row)
+		sb.WriteString(strconv.Itoa(len(str)))
+		sb.WriteByte(':')
+		sb.WriteString(str)
+	}
+	return sb.String()
+}
+
+// anyNull reports whether any of the columns is null at row
+func anyNull(cols []arrow.Array, row int) bool {
+	for _, col := range cols {
+		if col.IsNull(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupIndices returns the distinct keys of the non-null values of the array
+// in order of first appearance, the row indices of each key, and the indices
+// of the null rows. Keys are the values' string forms.
+func groupIndices(col arrow.Array) (keys []string, groups map[string][]int64, nullRows []int64) {
+	groups = make(map[string][]int64)
+	for i := 0; i < col.Len(); i++ {
+		if col.IsNull(i) {
+			nullRows = append(nullRows, int64(i))
+			continue
+		}
+		key := col.ValueStr(i)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], int64(i))
+	}
+	return keys, groups, nullRows
+}
+
+// takeRecordRows returns a new record with the given rows of the input
+func takeRecordRows(ctx context.Context, input arrow.Record, rows []int64) (arrow.Record, error) {
+	mem, _ := contextAllocator(ctx)
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.AppendValues(rows, nil)
+	indices := builder.NewArray()
+	defer indices.Release()
+
+	return TakeRecord(ctx, input, indices)
+}
+
+// takeArrayRows returns a new array with the given elements of the input
+func takeArrayRows(ctx context.Context, input arrow.Array, rows []int64, opts ...TakeOptions) (arrow.Array, error) {
+	mem, _ := contextAllocator(ctx)
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	builder.AppendValues(rows, nil)
+	indices := builder.NewArray()
+	defer indices.Release()
+
+	return TakeWithIndices(ctx, input, indices, opts...)
+}
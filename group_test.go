@@ -0,0 +1,490 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_splitRecordByColumn() {
+	// Create a test record
+	segmentBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer segmentBuilder.Release()
+	segmentBuilder.AppendValues([]string{"retail", "wholesale", "retail", "retail"}, nil)
+	segments := segmentBuilder.NewArray()
+	defer segments.Release()
+
+	revenueBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer revenueBuilder.Release()
+	revenueBuilder.AppendValues([]float64{10, 500, 25, 40}, nil)
+	revenue := revenueBuilder.NewArray()
+	defer revenue.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "segment", Type: arrow.BinaryTypes.String},
+		{Name: "revenue", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{segments, revenue}, 4)
+	defer rec.Release()
+
+	// Split into one record per segment
+	ctx := context.Background()
+	groups, nulls, err := archery.SplitRecordByColumn(ctx, rec, "segment")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	for _, group := range groups {
+		defer archery.ReleaseRecord(group)
+	}
+
+	// Print each group's revenue
+	fmt.Println("Retail:", groups["retail"].Column(1))
+	fmt.Println("Wholesale:", groups["wholesale"].Column(1))
+	fmt.Println("Null keys:", nulls)
+
+	// Output:
+	// Retail: [10 25 40]
+	// Wholesale: [500]
+	// Null keys: <nil>
+}
+
+func Example_splitRecordByColumnNulls() {
+	// A key column holding the literal string "(null)" and a real null
+	keyBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer keyBuilder.Release()
+	keyBuilder.AppendValues([]string{"(null)", "", "(null)"}, []bool{true, false, true})
+	keys := keyBuilder.NewArray()
+	defer keys.Release()
+
+	valBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer valBuilder.Release()
+	valBuilder.AppendValues([]int64{1, 2, 3}, nil)
+	vals := valBuilder.NewArray()
+	defer vals.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "key", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{keys, vals}, 3)
+	defer rec.Release()
+
+	// The string and the null stay in separate groups
+	ctx := context.Background()
+	groups, nulls, err := archery.SplitRecordByColumn(ctx, rec, "key")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	for _, group := range groups {
+		defer archery.ReleaseRecord(group)
+	}
+	defer archery.ReleaseRecord(nulls)
+
+	fmt.Println("Groups:", len(groups))
+	fmt.Println("String \"(null)\":", groups["(null)"].Column(1))
+	fmt.Println("Null key:", nulls.Column(1))
+
+	// Output:
+	// Groups: 1
+	// String "(null)": [1 3]
+	// Null key: [2]
+}
+
+func Example_topNPerGroup() {
+	// Create a test record of product sales
+	categoryBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer categoryBuilder.Release()
+	categoryBuilder.AppendValues([]string{"toys", "books", "toys", "toys", "books", "garden", "toys"}, nil)
+	categories := categoryBuilder.NewArray()
+	defer categories.Release()
+
+	productBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer productBuilder.Release()
+	productBuilder.AppendValues([]string{"kite", "atlas", "yoyo", "robot", "novel", "rake", "puzzle"}, nil)
+	products := productBuilder.NewArray()
+	defer products.Release()
+
+	salesBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer salesBuilder.Release()
+	salesBuilder.AppendValues([]int64{30, 12, 5, 80, 40, 7, 30}, nil)
+	sales := salesBuilder.NewArray()
+	defer sales.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "category", Type: arrow.BinaryTypes.String},
+		{Name: "product", Type: arrow.BinaryTypes.String},
+		{Name: "sales", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{categories, products, sales}, 7)
+	defer rec.Release()
+
+	// Keep the two best-selling products per category
+	ctx := context.Background()
+	top, err := archery.TopNPerGroup(ctx, rec, []string{"category"}, "sales", 2, archery.Descending)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer top.Release()
+
+	fmt.Println("Category:", top.Column(0))
+	fmt.Println("Product:", top.Column(1))
+	fmt.Println("Sales:", top.Column(2))
+
+	// Output:
+	// Category: ["toys" "toys" "books" "books" "garden"]
+	// Product: ["robot" "kite" "novel" "atlas" "rake"]
+	// Sales: [80 30 40 12 7]
+}
+
+func Example_argMaxPerGroup() {
+	// Create a test record of user events
+	userBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer userBuilder.Release()
+	userBuilder.AppendValues([]string{"ann", "bob", "ann", "bob", "cid"}, nil)
+	users := userBuilder.NewArray()
+	defer users.Release()
+
+	tsBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer tsBuilder.Release()
+	tsBuilder.AppendValues([]int64{100, 105, 130, 101, 90}, nil)
+	timestamps := tsBuilder.NewArray()
+	defer timestamps.Release()
+
+	eventBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer eventBuilder.Release()
+	eventBuilder.AppendValues([]string{"login", "login", "logout", "click", "login"}, nil)
+	events := eventBuilder.NewArray()
+	defer events.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "user", Type: arrow.BinaryTypes.String},
+		{Name: "ts", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "event", Type: arrow.BinaryTypes.String},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{users, timestamps, events}, 5)
+	defer rec.Release()
+
+	// Find the latest event of each user
+	ctx := context.Background()
+	indices, err := archery.ArgMaxPerGroup(ctx, rec, []string{"user"}, "ts")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer indices.Release()
+	fmt.Println("Rows:", indices)
+
+	// Reconstruct the whole rows
+	latest, err := archery.TakeRecord(ctx, rec, indices)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer latest.Release()
+	fmt.Println("User:", latest.Column(0))
+	fmt.Println("Event:", latest.Column(2))
+
+	// Output:
+	// Rows: [2 1 4]
+	// User: ["ann" "bob" "cid"]
+	// Event: ["logout" "login" "login"]
+}
+
+func Example_groupTransform() {
+	// Create a test record of scores per class
+	classBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer classBuilder.Release()
+	classBuilder.AppendValues([]string{"a", "b", "a", "b", "a"}, nil)
+	classes := classBuilder.NewArray()
+	defer classes.Release()
+
+	scoreBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]float64{70, 90, 80, 60, 90}, nil)
+	scores := scoreBuilder.NewArray()
+	defer scores.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "class", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{classes, scores}, 5)
+	defer rec.Release()
+
+	// Attach each class's mean score to every row of the class
+	ctx := context.Background()
+	withMean, err := archery.GroupTransform(ctx, rec, []string{"class"}, "score", archery.Float64Aggregator(archery.Mean))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer withMean.Release()
+
+	fmt.Println("Columns:", archery.ColumnNames(withMean))
+	fmt.Println("Class mean:", withMean.Column(2))
+
+	// Each row's deviation from its class mean
+	deviation, err := archery.Subtract(ctx, withMean.Column(1), withMean.Column(2))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer deviation.Release()
+	fmt.Println("Deviation:", deviation)
+
+	// Output:
+	// Columns: [class score score_transform]
+	// Class mean: [80 75 80 75 80]
+	// Deviation: [-10 15 0 -15 10]
+}
+
+func Example_groupByAuto() {
+	// Create a record of orders by region
+	regionBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer regionBuilder.Release()
+	regionBuilder.AppendValues([]string{"east", "west", "east", "west", "east"}, nil)
+	regions := regionBuilder.NewArray()
+	defer regions.Release()
+
+	repBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer repBuilder.Release()
+	repBuilder.AppendValues([]string{"ann", "bo", "cy", "di", "ed"}, nil)
+	reps := repBuilder.NewArray()
+	defer reps.Release()
+
+	unitsBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer unitsBuilder.Release()
+	unitsBuilder.AppendValues([]int64{3, 5, 4, 1, 2}, nil)
+	units := unitsBuilder.NewArray()
+	defer units.Release()
+
+	priceBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer priceBuilder.Release()
+	priceBuilder.AppendValues([]float64{10, 20, 30, 40, 50}, nil)
+	prices := priceBuilder.NewArray()
+	defer prices.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String},
+		{Name: "rep", Type: arrow.BinaryTypes.String},
+		{Name: "units", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{regions, reps, units, prices}, 5)
+	defer rec.Release()
+
+	// Sum units, keep the first rep, and average prices instead of summing
+	ctx := context.Background()
+	summary, err := archery.GroupByAuto(ctx, rec, []string{"region"}, map[string]archery.Aggregator{
+		"price": archery.Float64Aggregator(archery.Mean),
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer summary.Release()
+
+	table, err := archery.FormatRecord(summary)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(table)
+
+	// Output:
+	// region  rep  units  price  count
+	// east    ann  9      30     3
+	// west    bo   6      30     2
+}
+
+func Example_weightedMeanPerGroup() {
+	// Create a record of trades per symbol
+	symbolBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer symbolBuilder.Release()
+	symbolBuilder.AppendValues([]string{"ABC", "XYZ", "ABC", "XYZ"}, nil)
+	symbols := symbolBuilder.NewArray()
+	defer symbols.Release()
+
+	priceBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer priceBuilder.Release()
+	priceBuilder.AppendValues([]float64{10, 50, 12, 40}, nil)
+	prices := priceBuilder.NewArray()
+	defer prices.Release()
+
+	volumeBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer volumeBuilder.Release()
+	volumeBuilder.AppendValues([]int64{300, 100, 100, 300}, nil)
+	volumes := volumeBuilder.NewArray()
+	defer volumes.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "symbol", Type: arrow.BinaryTypes.String},
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "volume", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{symbols, prices, volumes}, 4)
+	defer rec.Release()
+
+	// Compute the volume-weighted average price of each symbol
+	ctx := context.Background()
+	vwap, err := archery.WeightedMeanPerGroup(ctx, rec, []string{"symbol"}, "price", "volume")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer vwap.Release()
+
+	table, err := archery.FormatRecord(vwap)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(table)
+
+	// Output:
+	// symbol  price_weighted_mean
+	// ABC     10.5
+	// XYZ     42.5
+}
+
+func Example_groupDropNullKeys() {
+	// Create a record of sales by a nullable region
+	regionBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer regionBuilder.Release()
+	regionBuilder.AppendValues([]string{"east", "", "east", "west", ""}, []bool{true, false, true, true, false})
+	regions := regionBuilder.NewArray()
+	defer regions.Release()
+
+	salesBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer salesBuilder.Release()
+	salesBuilder.AppendValues([]float64{10, 99, 30, 20, 1}, nil)
+	sales := salesBuilder.NewArray()
+	defer sales.Release()
+
+	weightBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer weightBuilder.Release()
+	weightBuilder.AppendValues([]float64{1, 1, 3, 1, 1}, nil)
+	weights := weightBuilder.NewArray()
+	defer weights.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "sales", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "weight", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{regions, sales, weights}, 5)
+	defer rec.Release()
+
+	ctx := context.Background()
+	dropNulls := archery.GroupOptions{DropNullKeys: true}
+
+	// By default the null region is a group of its own
+	summary, err := archery.GroupByAuto(ctx, rec, []string{"region"}, nil)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Regions:", summary.Column(0), "Sales:", summary.Column(1))
+	summary.Release()
+
+	// Dropping null keys leaves only the real regions
+	summary, err = archery.GroupByAuto(ctx, rec, []string{"region"}, nil, dropNulls)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Regions:", summary.Column(0), "Sales:", summary.Column(1), "Count:", summary.Column(3))
+	summary.Release()
+
+	// Transformed rows with a null key are kept but get a null
+	withTotal, err := archery.GroupTransform(ctx, rec, []string{"region"}, "sales", archery.Sum, dropNulls)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Region total:", withTotal.Column(3))
+	withTotal.Release()
+
+	means, err := archery.WeightedMeanPerGroup(ctx, rec, []string{"region"}, "sales", "weight", dropNulls)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Weighted means:", means.Column(0), means.Column(1))
+	means.Release()
+
+	// Output:
+	// Regions: ["east" (null) "west"] Sales: [40 100 20]
+	// Regions: ["east" "west"] Sales: [40 20] Count: [2 1]
+	// Region total: [40 (null) 40 20 (null)]
+	// Weighted means: ["east" "west"] [25 20]
+}
+
+func Example_groupByAutoParallel() {
+	// Create a record of 10,000 readings from 500 sensors
+	sensorBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer sensorBuilder.Release()
+	readingBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer readingBuilder.Release()
+	for i := 0; i < 10000; i++ {
+		sensorBuilder.Append(int64(i % 500))
+		readingBuilder.Append(float64(i) / 4)
+	}
+	sensors := sensorBuilder.NewArray()
+	defer sensors.Release()
+	readings := readingBuilder.NewArray()
+	defer readings.Release()
+
+	rec, err := archery.ZipArrays([]string{"sensor", "reading"}, []arrow.Array{sensors, readings})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rec.Release()
+
+	// Aggregate the groups serially and on up to four goroutines, allowing
+	// four even on a single-CPU machine
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	ctx := context.Background()
+	overrides := map[string]archery.Aggregator{"reading": archery.Float64Aggregator(archery.StandardDeviation)}
+	serial, err := archery.GroupByAuto(ctx, rec, []string{"sensor"}, overrides)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer serial.Release()
+	parallel, err := archery.GroupByAuto(ctx, rec, []string{"sensor"}, overrides, archery.GroupOptions{Parallelism: 4})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer parallel.Release()
+	fmt.Println("Groups:", parallel.NumRows())
+	fmt.Println("Identical:", array.RecordEqual(serial, parallel))
+
+	// Errors are reported for the same group as on the serial path
+	failing := map[string]archery.Aggregator{"reading": func(ctx context.Context, input arrow.Array) (interface{}, error) {
+		if input.(*array.Float64).Value(0) >= 100 {
+			return nil, fmt.Errorf("reading too high")
+		}
+		return nil, nil
+	}}
+	_, serialErr := archery.GroupByAuto(ctx, rec, []string{"sensor"}, failing)
+	_, parallelErr := archery.GroupByAuto(ctx, rec, []string{"sensor"}, failing, archery.GroupOptions{Parallelism: 4})
+	fmt.Println("Error:", parallelErr)
+	fmt.Println("Same error:", serialErr.Error() == parallelErr.Error())
+
+	// Output:
+	// Groups: 500
+	// Identical: true
+	// Error: error aggregating group 400 of column reading: reading too high
+	// Same error: true
+}
@@ -0,0 +1,115 @@
+package archery
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/zeebo/xxh3"
+)
+
+// HASHING OPERATIONS
+
+// HashRecord returns a 64-bit content hash of the record's schema and data,
+// suitable as a cache key. Only logical content is hashed, so slices and
+// freshly built records with the same schema and values hash equally, and the
+// hash is the same across processes and runs.
+//
+// The hash is order-sensitive: the same rows in a different order produce a
+// different hash. Sort the record first (e.g. with SortRecord) when row order
+// should not matter.
+//
+// Fixed-width values are hashed by their bytes, so floats that compare equal
+// with different bits, such as 0 and -0, hash differently. The hash is
+// XXH3, which is fast but not cryptographic: distinct records collide with a
+// chance of about one in 2^64 per pair, and inputs can be crafted to collide.
+// Where a collision would return wrong results, confirm a cache hit with
+// array.RecordEqual.
+func HashRecord(rec arrow.Record) (uint64, error) {
+	h := xxh3.New()
+	writeHashString(h, rec.Schema().Fingerprint())
+	writeHashUint64(h, uint64(rec.NumRows()))
+
+	for _, col := range rec.Columns() {
+		hashColumn(h, col)
+	}
+
+	return h.Sum64(), nil
+}
+
+// hashColumn writes the logical values of a column to the hash. Each value is
+// tagged with its validity so nulls never collide with values, and the bytes
+// under null slots, which are undefined, are skipped.
+func hashColumn(h io.Writer, col arrow.Array) {
+	// Booleans are bit-packed and dictionaries hold indices, so neither is
+	// hashed as whole bytes of values
+	fixed, ok := col.DataType().(arrow.FixedWidthDataType)
+	if ok && col.DataType().ID() != arrow.DICTIONARY && fixed.BitWidth()%8 == 0 && len(col.Data().Buffers()) > 1 {
+		width := fixed.BitWidth() / 8
+		values := col.Data().Buffers()[1].Bytes()[col.Data().Offset()*width:]
+		if col.NullN() == 0 {
+			// Without nulls the values are contiguous and hashed at once
+			h.Write([]byte{1})
+			h.Write(values[:col.Len()*width])
+			return
+		}
+		h.Write([]byte{0})
+		for i := 0; i < col.Len(); i++ {
+			if col.IsNull(i) {
+				h.Write([]byte{0})
+				continue
+			}
+			h.Write([]byte{1})
+			h.Write(values[i*width : (i+1)*width])
+		}
+		return
+	}
+
+	h.Write([]byte{0})
+	for i := 0; i < col.Len(); i++ {
+		if col.IsNull(i) {
+			h.Write([]byte{0})
+			continue
+		}
+		h.Write([]byte{1})
+		switch arr := col.(type) {
+		case *array.Boolean:
+			if arr.Value(i) {
+				h.Write([]byte{1})
+			} else {
+				h.Write([]byte{0})
+			}
+		case *array.String:
+			writeHashString(h, arr.Value(i))
+		case *array.LargeString:
+			writeHashString(h, arr.Value(i))
+		case *array.Binary:
+			writeHashBytes(h, arr.Value(i))
+		case *array.LargeBinary:
+			writeHashBytes(h, arr.Value(i))
+		default:
+			// Nested and other types are hashed by their string form
+			writeHashString(h, col.ValueStr(i))
+		}
+	}
+}
+
+// writeHashString writes a length-prefixed string to the hash
+func writeHashString(w io.Writer, s string) {
+	writeHashUint64(w, uint64(len(s)))
+	io.WriteString(w, s)
+}
+
+// writeHashBytes writes a length-prefixed byte slice to the hash
+func writeHashBytes(w io.Writer, b []byte) {
+	writeHashUint64(w, uint64(len(b)))
+	w.Write(b)
+}
+
+// writeHashUint64 writes a fixed-width integer to the hash
+func writeHashUint64(w io.Writer, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	w.Write(buf[:])
+}
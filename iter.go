@@ -0,0 +1,87 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// ROW ITERATION
+
+// ForEachRow calls fn for each row of the record with the row's cell values as
+// returned by ValueAt. The values slice is reused between calls to avoid a
+// per-row allocation, so fn must copy it if it needs to keep it. Iteration stops
+// at the first error returned by fn or when the context is cancelled.
+func ForEachRow(ctx context.Context, rec arrow.Record, fn func(row int, values []interface{}) error) error {
+	cols := rec.Columns()
+	values := make([]interface{}, len(cols))
+	for row := 0; row < int(rec.NumRows()); row++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i, col := range cols {
+			values[i] = ValueAt(col, row)
+		}
+		if err := fn(row, values); err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+	}
+	return nil
+}
+
+// FilterStream returns an iterator over the indices of the rows for which
+// predicate returns true. Rows are tested lazily as the iterator advances, so
+// breaking out of the loop stops the scan without building a mask. Iteration
+// also stops when the context is cancelled; check ctx.Err() afterwards to tell
+// a cancelled scan from a completed one.
+func FilterStream(ctx context.Context, rec arrow.Record, predicate func(row int) bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for row := 0; row < int(rec.NumRows()); row++ {
+			if ctx.Err() != nil {
+				return
+			}
+			if predicate(row) && !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+// VALUE ITERATION
+
+// Values returns an iterator over the index and value of each element of a
+// typed array such as *array.Int64 or *array.String. Null elements yield a nil
+// pointer.
+func Values[T any, A valuer[T]](arr A) iter.Seq2[int, *T] {
+	return func(yield func(int, *T) bool) {
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				if !yield(i, nil) {
+					return
+				}
+				continue
+			}
+			v := arr.Value(i)
+			if !yield(i, &v) {
+				return
+			}
+		}
+	}
+}
+
+// NonNullValues returns an iterator over the index and value of each non-null
+// element of a typed array, skipping nulls
+func NonNullValues[T any, A valuer[T]](arr A) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				continue
+			}
+			if !yield(i, arr.Value(i)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_forEachRow() {
+	// Create a test record
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"alice", "bob"}, nil)
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	scoreBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]float64{9.5, 0}, []bool{true, false})
+	scores := scoreBuilder.NewArray()
+	defer scores.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{names, scores}, 2)
+	defer rec.Release()
+
+	// Visit each row
+	ctx := context.Background()
+	err := archery.ForEachRow(ctx, rec, func(row int, values []interface{}) error {
+		fmt.Println(row, values)
+		return nil
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Output:
+	// 0 [alice 9.5]
+	// 1 [bob <nil>]
+}
+
+func Example_values() {
+	// Create a test array with a null
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{10, 0, 30}, []bool{true, false, true})
+	arr := builder.NewInt64Array()
+	defer arr.Release()
+
+	// Range over every element, with nulls as nil pointers
+	for i, v := range archery.Values(arr) {
+		if v == nil {
+			fmt.Println(i, "null")
+			continue
+		}
+		fmt.Println(i, *v)
+	}
+
+	// Range over the non-null elements only
+	var sum int64
+	for _, v := range archery.NonNullValues(arr) {
+		sum += v
+	}
+	fmt.Println("Sum:", sum)
+
+	// Output:
+	// 0 10
+	// 1 null
+	// 2 30
+	// Sum: 40
+}
+
+func Example_filterStream() {
+	// Create a test record
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1.0, 9.5, 1.2, 8.7, 9.9, 1.1}, nil)
+	latency := builder.NewFloat64Array()
+	defer latency.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "latency", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{latency}, 6)
+	defer rec.Release()
+
+	// Find the first two slow rows and stop scanning
+	ctx := context.Background()
+	var found []int
+	for row := range archery.FilterStream(ctx, rec, func(row int) bool {
+		return latency.Value(row) > 5
+	}) {
+		found = append(found, row)
+		if len(found) == 2 {
+			break
+		}
+	}
+
+	// Print the matching rows
+	fmt.Println("Slow rows:", found)
+
+	// Output:
+	// Slow rows: [1 3]
+}
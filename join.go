@@ -0,0 +1,250 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// JOIN OPERATIONS
+
+// SemiJoin returns the rows of left whose leftKey value occurs in right's
+// rightKey column. Only left's columns are returned and each left row appears
+// at most once. Null keys never match.
+func SemiJoin(ctx context.Context, left, right arrow.Record, leftKey, rightKey string) (arrow.Record, error) {
+	mask, err := keyMembershipMask(ctx, left, right, leftKey, rightKey)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(mask)
+
+	return FilterRecord(ctx, left, mask)
+}
+
+// AntiJoin returns the rows of left whose leftKey value does not occur in
+// right's rightKey column. Only left's columns are returned. Null keys never
+// match, so rows with a null key are always kept.
+func AntiJoin(ctx context.Context, left, right arrow.Record, leftKey, rightKey string) (arrow.Record, error) {
+	mask, err := keyMembershipMask(ctx, left, right, leftKey, rightKey)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(mask)
+
+	inverted, err := Invert(ctx, mask)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(inverted)
+
+	return FilterRecord(ctx, left, inverted)
+}
+
+// DefaultCrossJoinMaxRows is the output row limit CrossJoin applies when
+// CrossJoinOptions.MaxRows is zero
+const DefaultCrossJoinMaxRows int64 = 10_000_000
+
+// CrossJoinOptions controls the size guard and column naming of CrossJoin
+type CrossJoinOptions struct {
+	// MaxRows is the largest number of output rows allowed. Zero means
+	// DefaultCrossJoinMaxRows and a negative value disables the limit.
+	MaxRows int64
+	// Duplicates decides how columns of b whose names occur in a are handled
+	Duplicates DuplicateColumnPolicy
+	// LeftSuffix and RightSuffix rename colliding columns under the suffix
+	// policies. Empty values mean DefaultLeftSuffix and DefaultRightSuffix.
+	LeftSuffix  string
+	RightSuffix string
+}
+
+// CrossJoin returns every combination of a row of a with a row of b, with a's
+// columns followed by b's. Rows are ordered by a's row, then b's row. Column
+// name collisions are an error unless another DuplicateColumnPolicy is set. The
+// join errors before allocating anything if the output would exceed the row
+// limit.
+func CrossJoin(ctx context.Context, a, b arrow.Record, opts ...CrossJoinOptions) (arrow.Record, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	var options CrossJoinOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+	maxRows := DefaultCrossJoinMaxRows
+	if options.MaxRows != 0 {
+		maxRows = options.MaxRows
+	}
+
+	leftSuffix, rightSuffix := DefaultLeftSuffix, DefaultRightSuffix
+	if options.LeftSuffix != "" {
+		leftSuffix = options.LeftSuffix
+	}
+	if options.RightSuffix != "" {
+		rightSuffix = options.RightSuffix
+	}
+
+	fields, keep, err := mergeFields(a.Schema(), b.Schema(), options.Duplicates, leftSuffix, rightSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	aRows, bRows := a.NumRows(), b.NumRows()
+	if bRows > 0 && aRows > math.MaxInt64/bRows {
+		return nil, fmt.Errorf("cross join of %d and %d rows overflows", aRows, bRows)
+	}
+	numRows := aRows * bRows
+	if maxRows > 0 && numRows > maxRows {
+		return nil, fmt.Errorf("cross join would produce %d rows, exceeding the limit of %d", numRows, maxRows)
+	}
+
+	// Repeat each row of a once per row of b, and cycle through b once per row of a
+	aIndices := make([]int64, 0, numRows)
+	bIndices := make([]int64, 0, numRows)
+	for i := int64(0); i < aRows; i++ {
+		for j := int64(0); j < bRows; j++ {
+			aIndices = append(aIndices, i)
+			bIndices = append(bIndices, j)
+		}
+	}
+
+	left, err := takeRecordRows(ctx, a, aIndices)
+	if err != nil {
+		return nil, err
+	}
+	defer left.Release()
+
+	right, err := takeRecordRows(ctx, b, bIndices)
+	if err != nil {
+		return nil, err
+	}
+	defer right.Release()
+
+	cols := append([]arrow.Array{}, left.Columns()...)
+	for i, col := range right.Columns() {
+		if keep[i] {
+			cols = append(cols, col)
+		}
+	}
+	return array.NewRecord(arrow.NewSchema(fields, nil), cols, numRows), nil
+}
+
+// keyMembershipMask returns a mask of the left rows whose key occurs in the right key column
+func keyMembershipMask(ctx context.Context, left, right arrow.Record, leftKey, rightKey string) (arrow.Array, error) {
+	leftCol, err := GetColumn(left, leftKey)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(leftCol)
+
+	rightCol, err := GetColumn(right, rightKey)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(rightCol)
+
+	if !arrow.TypeEqual(leftCol.DataType(), rightCol.DataType()) {
+		return nil, fmt.Errorf("key columns have different types: %s and %s", leftCol.DataType(), rightCol.DataType())
+	}
+
+	return IsIn(ctx, leftCol, rightCol)
+}
+
+// RecordDiff compares two versions of a dataset keyed by keyCols. It returns
+// the rows of a whose key does not occur in b, the rows of b whose key does
+// not occur in a, and the rows of b whose key occurs in a but whose other
+// columns differ from a's row. onlyInA keeps a's row order; onlyInB and
+// changed keep b's. Both records must have the same schema and keys must be
+// unique within each record. Unlike the joins, a null key matches a null key,
+// so rows keyed by nulls are compared too. Values are compared by their
+// string forms, so NaN equals NaN. The caller must release all three records.
+func RecordDiff(ctx context.Context, a, b arrow.Record, keyCols []string) (onlyInA, onlyInB, changed arrow.Record, err error) {
+	if len(keyCols) == 0 {
+		return nil, nil, nil, fmt.Errorf("at least one key column is required")
+	}
+	if !a.Schema().Equal(b.Schema()) {
+		return nil, nil, nil, fmt.Errorf("records have different schemas: %s and %s", a.Schema(), b.Schema())
+	}
+
+	isKey := make([]bool, a.NumCols())
+	for _, name := range keyCols {
+		idx, err := GetColumnIndex(a, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		isKey[idx] = true
+	}
+	split := func(rec arrow.Record) (keys, values []arrow.Array) {
+		for i, col := range rec.Columns() {
+			if isKey[i] {
+				keys = append(keys, col)
+			} else {
+				values = append(values, col)
+			}
+		}
+		return keys, values
+	}
+	aKeys, aValues := split(a)
+	bKeys, bValues := split(b)
+
+	// Index a's rows by key
+	aRowByKey := make(map[string]int, a.NumRows())
+	for row := 0; row < int(a.NumRows()); row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		key := compositeKey(aKeys, row)
+		if _, ok := aRowByKey[key]; ok {
+			return nil, nil, nil, fmt.Errorf("duplicate key in first record at row %d", row)
+		}
+		aRowByKey[key] = row
+	}
+
+	// Match b's rows against a, noting which of a's rows were seen
+	matched := make([]bool, a.NumRows())
+	seen := make(map[string]struct{}, b.NumRows())
+	var bOnlyRows, changedRows []int64
+	for row := 0; row < int(b.NumRows()); row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		key := compositeKey(bKeys, row)
+		if _, ok := seen[key]; ok {
+			return nil, nil, nil, fmt.Errorf("duplicate key in second record at row %d", row)
+		}
+		seen[key] = struct{}{}
+
+		aRow, ok := aRowByKey[key]
+		if !ok {
+			bOnlyRows = append(bOnlyRows, int64(row))
+			continue
+		}
+		matched[aRow] = true
+		if compositeKey(aValues, aRow) != compositeKey(bValues, row) {
+			changedRows = append(changedRows, int64(row))
+		}
+	}
+
+	var aOnlyRows []int64
+	for row, ok := range matched {
+		if !ok {
+			aOnlyRows = append(aOnlyRows, int64(row))
+		}
+	}
+
+	if onlyInA, err = takeRecordRows(ctx, a, aOnlyRows); err != nil {
+		return nil, nil, nil, err
+	}
+	if onlyInB, err = takeRecordRows(ctx, b, bOnlyRows); err != nil {
+		onlyInA.Release()
+		return nil, nil, nil, err
+	}
+	if changed, err = takeRecordRows(ctx, b, changedRows); err != nil {
+		onlyInA.Release()
+		onlyInB.Release()
+		return nil, nil, nil, err
+	}
+	return onlyInA, onlyInB, changed, nil
+}
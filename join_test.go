@@ -0,0 +1,212 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_semiJoin() {
+	// Create a transactions record
+	userBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer userBuilder.Release()
+	userBuilder.AppendValues([]int64{1, 2, 3, 2, 4}, nil)
+	users := userBuilder.NewArray()
+	defer users.Release()
+
+	amountBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer amountBuilder.Release()
+	amountBuilder.AppendValues([]float64{10, 20, 30, 40, 50}, nil)
+	amounts := amountBuilder.NewArray()
+	defer amounts.Release()
+
+	txSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "user_id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	transactions := array.NewRecord(txSchema, []arrow.Array{users, amounts}, 5)
+	defer transactions.Release()
+
+	// Create an active users record
+	userBuilder.AppendValues([]int64{2, 4}, nil)
+	active := userBuilder.NewArray()
+	defer active.Release()
+
+	activeSchema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	activeUsers := array.NewRecord(activeSchema, []arrow.Array{active}, 2)
+	defer activeUsers.Release()
+
+	// Keep transactions of active users, and find the orphans
+	ctx := context.Background()
+	kept, err := archery.SemiJoin(ctx, transactions, activeUsers, "user_id", "id")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(kept)
+
+	orphans, err := archery.AntiJoin(ctx, transactions, activeUsers, "user_id", "id")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(orphans)
+
+	// Print the amounts of each side
+	fmt.Println("Active:", kept.Column(1))
+	fmt.Println("Orphans:", orphans.Column(1))
+
+	// Output:
+	// Active: [20 40 50]
+	// Orphans: [10 30]
+}
+
+func Example_crossJoin() {
+	// Create two parameter records that share a column name
+	rateBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer rateBuilder.Release()
+	rateBuilder.AppendValues([]float64{0.1, 0.01}, nil)
+	rates := rateBuilder.NewArray()
+	defer rates.Release()
+
+	intBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer intBuilder.Release()
+	intBuilder.AppendValues([]int64{1, 2}, nil)
+	leftIDs := intBuilder.NewArray()
+	defer leftIDs.Release()
+	intBuilder.AppendValues([]int64{3, 5, 8}, nil)
+	depths := intBuilder.NewArray()
+	defer depths.Release()
+	intBuilder.AppendValues([]int64{1, 2, 3}, nil)
+	rightIDs := intBuilder.NewArray()
+	defer rightIDs.Release()
+
+	left := array.NewRecord(arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "rate", Type: arrow.PrimitiveTypes.Float64},
+	}, nil), []arrow.Array{leftIDs, rates}, 2)
+	defer left.Release()
+
+	right := array.NewRecord(arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "depth", Type: arrow.PrimitiveTypes.Int64},
+	}, nil), []arrow.Array{rightIDs, depths}, 3)
+	defer right.Release()
+
+	// Build the parameter grid
+	ctx := context.Background()
+	grid, err := archery.CrossJoin(ctx, left, right, archery.CrossJoinOptions{Duplicates: archery.DuplicateSuffixRight})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer grid.Release()
+
+	fmt.Println("Columns:", archery.ColumnNames(grid))
+	fmt.Println("Rate:", grid.Column(1))
+	fmt.Println("Depth:", grid.Column(3))
+
+	// By default a name collision is an error
+	_, err = archery.CrossJoin(ctx, left, right)
+	fmt.Println("Error:", err)
+
+	// A tight row limit rejects the join
+	_, err = archery.CrossJoin(ctx, left, right, archery.CrossJoinOptions{MaxRows: 4, Duplicates: archery.DuplicateKeepLeft})
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Columns: [id rate id_right depth]
+	// Rate: [0.1 0.1 0.1 0.01 0.01 0.01]
+	// Depth: [3 5 8 3 5 8]
+	// Error: duplicate column name: id
+	// Error: cross join would produce 6 rows, exceeding the limit of 4
+}
+
+func Example_crossJoinSuffixes() {
+	// Two snapshots of the same table share a "status" column
+	statusBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer statusBuilder.Release()
+	statusBuilder.AppendValues([]string{"pending"}, nil)
+	before := statusBuilder.NewArray()
+	defer before.Release()
+	statusBuilder.AppendValues([]string{"shipped", "cancelled"}, nil)
+	after := statusBuilder.NewArray()
+	defer after.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "status", Type: arrow.BinaryTypes.String}}, nil)
+	old := array.NewRecord(schema, []arrow.Array{before}, 1)
+	defer old.Release()
+	current := array.NewRecord(schema, []arrow.Array{after}, 2)
+	defer current.Release()
+
+	// Rename both sides so the columns can be compared
+	ctx := context.Background()
+	pairs, err := archery.CrossJoin(ctx, old, current, archery.CrossJoinOptions{
+		Duplicates:  archery.DuplicateSuffixBoth,
+		LeftSuffix:  "_old",
+		RightSuffix: "_new",
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer pairs.Release()
+
+	fmt.Println("Columns:", archery.ColumnNames(pairs))
+	fmt.Println("Old:", pairs.Column(0))
+	fmt.Println("New:", pairs.Column(1))
+
+	// Output:
+	// Columns: [status_old status_new]
+	// Old: ["pending" "pending"]
+	// New: ["shipped" "cancelled"]
+}
+
+func Example_recordDiff() {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+
+	// newVersion builds one version of the price list
+	newVersion := func(ids []int64, prices []float64) arrow.Record {
+		idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer idBuilder.Release()
+		idBuilder.AppendValues(ids, nil)
+		idArr := idBuilder.NewArray()
+		defer idArr.Release()
+
+		priceBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+		defer priceBuilder.Release()
+		priceBuilder.AppendValues(prices, nil)
+		priceArr := priceBuilder.NewArray()
+		defer priceArr.Release()
+
+		return array.NewRecord(schema, []arrow.Array{idArr, priceArr}, int64(len(ids)))
+	}
+	before := newVersion([]int64{1, 2, 3}, []float64{9.5, 4.0, 7.25})
+	defer before.Release()
+	after := newVersion([]int64{4, 3, 2}, []float64{1.0, 7.25, 4.5})
+	defer after.Release()
+
+	ctx := context.Background()
+	onlyBefore, onlyAfter, changed, err := archery.RecordDiff(ctx, before, after, []string{"id"})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecords(onlyBefore, onlyAfter, changed)
+
+	fmt.Println("Removed ids:", onlyBefore.Column(0))
+	fmt.Println("Added ids:", onlyAfter.Column(0))
+	fmt.Println("Changed ids:", changed.Column(0), "new prices:", changed.Column(1))
+
+	// Output:
+	// Removed ids: [1]
+	// Added ids: [4]
+	// Changed ids: [2] new prices: [4.5]
+}
@@ -0,0 +1,35 @@
+package archery
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PANIC RECOVERY
+
+// ErrPanic is wrapped by errors returned from SafeCall when fn panics
+var ErrPanic = errors.New("recovered panic")
+
+// SafeCall runs fn and converts a panic into an error wrapping ErrPanic, with
+// the panic value and stack trace in the message. A panic value that is an
+// error is wrapped as well, so errors.Is can match it. Some compute kernels
+// panic on malformed input instead of returning an error; wrap calls in
+// SafeCall where a bad input must not crash the process, such as in a request
+// handler.
+// Recovery is opt-in so that bugs still surface as panics in tests.
+func SafeCall[T any](fn func() (T, error)) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			result = zero
+			// Keep error panic values matchable, as for ErrMemoryLimitExceeded
+			if panicErr, ok := r.(error); ok {
+				err = fmt.Errorf("%w: %w\n%s", ErrPanic, panicErr, debug.Stack())
+				return
+			}
+			err = fmt.Errorf("%w: %v\n%s", ErrPanic, r, debug.Stack())
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,44 @@
+package archery_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_safeCall() {
+	// Create a test array
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3}, nil)
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Calls that succeed pass their result through
+	ctx := context.Background()
+	sorted, err := archery.SafeCall(func() (arrow.Array, error) {
+		return archery.Sort(ctx, arr, archery.Descending)
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(sorted)
+	fmt.Println("Sorted:", sorted)
+
+	// A panic is returned as an error instead of crashing
+	_, err = archery.SafeCall(func() (arrow.Array, error) {
+		var missing arrow.Array
+		return archery.Sort(ctx, missing, archery.Ascending)
+	})
+	fmt.Println("Recovered:", errors.Is(err, archery.ErrPanic))
+
+	// Output:
+	// Sorted: [3 2 1]
+	// Recovered: true
+}
@@ -0,0 +1,132 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// RECORD OPERATIONS
+
+// ShuffleRecord returns a new record with the rows of the input in a random order.
+// The permutation is determined by the seed: the same seed always yields the same
+// permutation for a given number of rows.
+func ShuffleRecord(ctx context.Context, input arrow.Record, seed int64) (arrow.Record, error) {
+	indices := shuffledIndices(input.NumRows(), seed)
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues(indices, nil)
+	indicesArr := builder.NewArray()
+	defer indicesArr.Release()
+
+	return TakeRecord(ctx, input, indicesArr)
+}
+
+// TrainTestSplit shuffles the input with the given seed and partitions it into two
+// disjoint records. The test record receives testFraction of the rows, rounded to
+// the nearest row but never leaving either side empty.
+func TrainTestSplit(ctx context.Context, input arrow.Record, testFraction float64, seed int64) (train, test arrow.Record, err error) {
+	if testFraction <= 0 || testFraction >= 1 {
+		return nil, nil, fmt.Errorf("test fraction must be in (0, 1), got %v", testFraction)
+	}
+
+	numRows := input.NumRows()
+	if numRows < 2 {
+		return nil, nil, fmt.Errorf("need at least 2 rows to split, got %d", numRows)
+	}
+
+	// Keep at least one row on each side
+	testRows := int64(math.Round(float64(numRows) * testFraction))
+	testRows = min(max(testRows, 1), numRows-1)
+
+	shuffled, err := ShuffleRecord(ctx, input, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer shuffled.Release()
+
+	// Slices share the shuffled buffers and hold their own references
+	test = shuffled.NewSlice(0, testRows)
+	train = shuffled.NewSlice(testRows, numRows)
+	return train, test, nil
+}
+
+// WeightedSampleRecord draws n rows with replacement, each with probability
+// proportional to its value in the weight column. Weights must be non-negative
+// and not all zero; null weights count as zero. The draw is determined by the
+// seed.
+func WeightedSampleRecord(ctx context.Context, input arrow.Record, weightCol string, n int, seed int64) (arrow.Record, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("sample size must be non-negative, got %d", n)
+	}
+
+	col, err := GetColumn(input, weightCol)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseArray(col)
+
+	weights, err := castFloat64(ctx, col)
+	if err != nil {
+		return nil, fmt.Errorf("weight column %s: %w", weightCol, err)
+	}
+	defer weights.Release()
+
+	// Build the cumulative distribution of the weights
+	cumulative := make([]float64, weights.Len())
+	var total float64
+	for i := 0; i < weights.Len(); i++ {
+		if weights.IsValid(i) {
+			w := weights.Value(i)
+			if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("weights must be non-negative and finite, got %v at row %d", w, i)
+			}
+			total += w
+		}
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("weights must not all be zero")
+	}
+
+	// Invert the distribution with a binary search per draw
+	rng := rand.New(rand.NewSource(seed))
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(n)
+	for i := 0; i < n; i++ {
+		target := rng.Float64() * total
+		// The first row whose cumulative weight exceeds the target owns it,
+		// which skips zero-weight rows
+		row := sort.Search(len(cumulative), func(j int) bool {
+			return cumulative[j] > target
+		})
+		builder.Append(int64(row))
+	}
+	indices := builder.NewArray()
+	defer indices.Release()
+
+	return TakeRecord(ctx, input, indices)
+}
+
+// shuffledIndices returns a seeded Fisher-Yates permutation of [0, n)
+func shuffledIndices(n int64, seed int64) []int64 {
+	indices := make([]int64, n)
+	for i := range indices {
+		indices[i] = int64(i)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	for i := len(indices) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		indices[i], indices[j] = indices[j], indices[i]
+	}
+	return indices
+}
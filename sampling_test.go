@@ -0,0 +1,145 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_shuffleRecord() {
+	// Create a test record with a null in the name column
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5, 6}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues(
+		[]string{"a", "b", "", "d", "e", "f"},
+		[]bool{true, true, false, true, true, true},
+	)
+	rec := builder.NewRecord()
+	defer rec.Release()
+
+	// Shuffle twice with the same seed and once with another
+	ctx := context.Background()
+	first, err := archery.ShuffleRecord(ctx, rec, 42)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(first)
+
+	second, err := archery.ShuffleRecord(ctx, rec, 42)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(second)
+
+	other, err := archery.ShuffleRecord(ctx, rec, 7)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(other)
+
+	// An empty record shuffles to an empty record
+	empty := rec.NewSlice(0, 0)
+	defer empty.Release()
+	shuffledEmpty, err := archery.ShuffleRecord(ctx, empty, 42)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(shuffledEmpty)
+
+	// Rows move as a unit, nulls included, and the same seed gives the same order
+	fmt.Println("IDs:", first.Column(0))
+	fmt.Println("Names:", first.Column(1))
+	fmt.Println("Same order:", array.RecordEqual(first, second))
+	fmt.Println("Seed 7 IDs:", other.Column(0))
+	fmt.Println("Empty rows:", shuffledEmpty.NumRows())
+
+	// Output:
+	// IDs: [5 2 4 1 3 6]
+	// Names: ["e" "b" "d" "a" (null) "f"]
+	// Same order: true
+	// Seed 7 IDs: [4 6 5 2 1 3]
+	// Empty rows: 0
+}
+
+func Example_trainTestSplit() {
+	// Create a test record
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	for i := int64(0); i < 10; i++ {
+		builder.Append(i)
+	}
+	ids := builder.NewArray()
+	defer ids.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids}, 10)
+	defer rec.Release()
+
+	// Hold out 30% of the rows for testing
+	ctx := context.Background()
+	train, test, err := archery.TrainTestSplit(ctx, rec, 0.3, 7)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(train)
+	defer archery.ReleaseRecord(test)
+
+	// Print the partition sizes
+	fmt.Println("Train rows:", train.NumRows())
+	fmt.Println("Test rows:", test.NumRows())
+
+	// Output:
+	// Train rows: 7
+	// Test rows: 3
+}
+
+func Example_weightedSampleRecord() {
+	// Create a test record where only one row carries weight
+	idBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]string{"a", "b", "c"}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	weightBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer weightBuilder.Release()
+	weightBuilder.AppendValues([]float64{0, 2.5, 0}, nil)
+	weights := weightBuilder.NewArray()
+	defer weights.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+		{Name: "weight", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{ids, weights}, 3)
+	defer rec.Release()
+
+	// Draw rows in proportion to their weight
+	ctx := context.Background()
+	sample, err := archery.WeightedSampleRecord(ctx, rec, "weight", 4, 42)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(sample)
+
+	// Print the sampled ids
+	fmt.Println("Sampled:", sample.Column(0))
+
+	// Output:
+	// Sampled: ["b" "b" "b" "b"]
+}
@@ -0,0 +1,140 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// SCALING OPERATIONS
+
+// ScalerMode selects how a Scaler normalizes values
+type ScalerMode int
+
+const (
+	// MinMaxScaling maps the fitted range [Min, Max] onto [0, 1]
+	MinMaxScaling ScalerMode = iota
+	// StandardScaling maps values to z-scores using the fitted Mean and Std
+	StandardScaling
+)
+
+// Scaler normalizes numeric arrays with parameters fitted on one array and
+// reused on others, so test data is scaled with training statistics. The
+// fitted parameters are exported for inspection; Std is the population
+// standard deviation, as returned by StandardDeviation.
+type Scaler struct {
+	Mode ScalerMode
+
+	Min  float64
+	Max  float64
+	Mean float64
+	Std  float64
+
+	fitted bool
+}
+
+// NewScaler returns an unfitted Scaler using the given mode
+func NewScaler(mode ScalerMode) *Scaler {
+	return &Scaler{Mode: mode}
+}
+
+// Fit computes the scaling parameters from the non-null values of the array
+func (s *Scaler) Fit(ctx context.Context, input arrow.Array) error {
+	values, err := nonNullFloat64s(input)
+	if err != nil {
+		return fmt.Errorf("scaler fit: %w", err)
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("scaler fit: no non-null values")
+	}
+
+	s.Min, s.Max = values[0], values[0]
+	var sum float64
+	for _, v := range values {
+		s.Min = min(s.Min, v)
+		s.Max = max(s.Max, v)
+		sum += v
+	}
+	s.Mean = sum / float64(len(values))
+
+	var sumSquaredDiff float64
+	for _, v := range values {
+		diff := v - s.Mean
+		sumSquaredDiff += diff * diff
+	}
+	s.Std = math.Sqrt(sumSquaredDiff / float64(len(values)))
+
+	s.fitted = true
+	return nil
+}
+
+// Fitted reports whether Fit has been called successfully
+func (s *Scaler) Fitted() bool {
+	return s.fitted
+}
+
+// Transform scales the array with the fitted parameters, returning a Float64
+// array. A zero range or standard deviation is treated as a scale of 1, so
+// constant data is only shifted. Nulls are preserved.
+func (s *Scaler) Transform(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+	offset, scale, err := s.params()
+	if err != nil {
+		return nil, err
+	}
+	return affine(ctx, input, func(v float64) float64 { return (v - offset) / scale })
+}
+
+// InverseTransform maps scaled values back to the original units, returning a
+// Float64 array. Nulls are preserved.
+func (s *Scaler) InverseTransform(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+	offset, scale, err := s.params()
+	if err != nil {
+		return nil, err
+	}
+	return affine(ctx, input, func(v float64) float64 { return v*scale + offset })
+}
+
+// params returns the offset and scale applied by Transform
+func (s *Scaler) params() (offset, scale float64, err error) {
+	if !s.fitted {
+		return 0, 0, fmt.Errorf("scaler has not been fitted")
+	}
+
+	switch s.Mode {
+	case MinMaxScaling:
+		offset, scale = s.Min, s.Max-s.Min
+	case StandardScaling:
+		offset, scale = s.Mean, s.Std
+	default:
+		return 0, 0, fmt.Errorf("unknown scaler mode %d", s.Mode)
+	}
+	if scale == 0 {
+		scale = 1
+	}
+	return offset, scale, nil
+}
+
+// affine applies fn to each non-null element of a numeric array
+func affine(ctx context.Context, input arrow.Array, fn func(float64) float64) (arrow.Array, error) {
+	floats, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("scaler: %w", err)
+	}
+	defer floats.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(floats.Len())
+	for i := 0; i < floats.Len(); i++ {
+		if floats.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(fn(floats.Value(i)))
+	}
+	return builder.NewArray(), nil
+}
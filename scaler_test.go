@@ -0,0 +1,55 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_scaler() {
+	// Create training and test arrays
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{10, 20, 30}, nil)
+	train := builder.NewFloat64Array()
+	defer train.Release()
+	builder.AppendValues([]float64{15, 40}, nil)
+	test := builder.NewFloat64Array()
+	defer test.Release()
+
+	// Fit on the training data only
+	ctx := context.Background()
+	scaler := archery.NewScaler(archery.MinMaxScaling)
+	if err := scaler.Fit(ctx, train); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Range:", scaler.Min, scaler.Max)
+
+	// Apply the training parameters to the test data
+	scaled, err := scaler.Transform(ctx, test)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(scaled)
+
+	restored, err := scaler.InverseTransform(ctx, scaled)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(restored)
+
+	// Print the results
+	fmt.Println("Scaled:", scaled)
+	fmt.Println("Restored:", restored)
+
+	// Output:
+	// Range: 10 30
+	// Scaled: [0.25 1.5]
+	// Restored: [15 40]
+}
@@ -1,175 +1,70 @@
 package archery
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/bitutil"
 	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
 // ARRAY OPERATIONS
 
-// Sort returns a sorted copy of the input array
+// Sort returns a sorted copy of the input array. The sort is stable: equal
+// values keep their relative input order. Nulls are placed first.
 func Sort(ctx context.Context, input arrow.Array, order SortOrder) (arrow.Array, error) {
-	// Get sort indices
-	indices, err := SortIndices(ctx, input, order)
+	sorted, indices, err := SortWithIndices(ctx, input, order)
 	if err != nil {
 		return nil, err
 	}
-	defer indices.Release()
+	indices.Release()
+
+	return sorted, nil
+}
+
+// SortWithIndices returns a sorted copy of the input array together with the
+// indices used to sort it, so the same ordering can be applied to parallel
+// arrays with TakeWithIndices. The caller must release both arrays.
+func SortWithIndices(ctx context.Context, input arrow.Array, order SortOrder) (sorted arrow.Array, indices arrow.Array, err error) {
+	// Get sort indices
+	indices, err = SortIndices(ctx, input, order)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Use take to reorder the input array
-	return TakeWithIndices(ctx, input, indices)
+	sorted, err = TakeWithIndices(ctx, input, indices)
+	if err != nil {
+		indices.Release()
+		return nil, nil, err
+	}
+	return sorted, indices, nil
 }
 
-// SortIndices returns the indices that would sort the input array
+// SortIndices returns the indices that would sort the input array. The sort is
+// stable: indices of equal values, including nulls, appear in ascending order.
+// Nulls are placed first regardless of the sort order. Booleans, integers,
+// floats, strings and the temporal types (dates, times, timestamps and
+// durations) are supported.
 func SortIndices(ctx context.Context, input arrow.Array, order SortOrder) (arrow.Array, error) {
-	// Implement sort_indices manually since the function is not available
-	length := input.Len()
-	indices := make([]int64, length)
-
-	// Initialize indices
-	for i := 0; i < length; i++ {
-		indices[i] = int64(i)
+	compare, err := valueComparator(input, order)
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort indices based on array values
-	switch input.DataType().ID() {
-	case arrow.BOOL:
-		boolArr := input.(*array.Boolean)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if boolArr.IsNull(int(indices[i])) {
-				return true
-			}
-			if boolArr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return !boolArr.Value(int(indices[i])) && boolArr.Value(int(indices[j]))
-			}
-			return boolArr.Value(int(indices[i])) && !boolArr.Value(int(indices[j]))
-		})
-	case arrow.INT8:
-		int8Arr := input.(*array.Int8)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if int8Arr.IsNull(int(indices[i])) {
-				return true
-			}
-			if int8Arr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return int8Arr.Value(int(indices[i])) < int8Arr.Value(int(indices[j]))
-			}
-			return int8Arr.Value(int(indices[i])) > int8Arr.Value(int(indices[j]))
-		})
-	case arrow.INT16:
-		int16Arr := input.(*array.Int16)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if int16Arr.IsNull(int(indices[i])) {
-				return true
-			}
-			if int16Arr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return int16Arr.Value(int(indices[i])) < int16Arr.Value(int(indices[j]))
-			}
-			return int16Arr.Value(int(indices[i])) > int16Arr.Value(int(indices[j]))
-		})
-	case arrow.INT32:
-		int32Arr := input.(*array.Int32)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if int32Arr.IsNull(int(indices[i])) {
-				return true
-			}
-			if int32Arr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return int32Arr.Value(int(indices[i])) < int32Arr.Value(int(indices[j]))
-			}
-			return int32Arr.Value(int(indices[i])) > int32Arr.Value(int(indices[j]))
-		})
-	case arrow.INT64:
-		int64Arr := input.(*array.Int64)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if int64Arr.IsNull(int(indices[i])) {
-				return true
-			}
-			if int64Arr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return int64Arr.Value(int(indices[i])) < int64Arr.Value(int(indices[j]))
-			}
-			return int64Arr.Value(int(indices[i])) > int64Arr.Value(int(indices[j]))
-		})
-	case arrow.FLOAT32:
-		float32Arr := input.(*array.Float32)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if float32Arr.IsNull(int(indices[i])) {
-				return true
-			}
-			if float32Arr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return float32Arr.Value(int(indices[i])) < float32Arr.Value(int(indices[j]))
-			}
-			return float32Arr.Value(int(indices[i])) > float32Arr.Value(int(indices[j]))
-		})
-	case arrow.FLOAT64:
-		float64Arr := input.(*array.Float64)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if float64Arr.IsNull(int(indices[i])) {
-				return true
-			}
-			if float64Arr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return float64Arr.Value(int(indices[i])) < float64Arr.Value(int(indices[j]))
-			}
-			return float64Arr.Value(int(indices[i])) > float64Arr.Value(int(indices[j]))
-		})
-	case arrow.STRING:
-		stringArr := input.(*array.String)
-		sort.SliceStable(indices, func(i, j int) bool {
-			// Handle nulls - nulls come first
-			if stringArr.IsNull(int(indices[i])) {
-				return true
-			}
-			if stringArr.IsNull(int(indices[j])) {
-				return false
-			}
-			// Compare values
-			if order == Ascending {
-				return stringArr.Value(int(indices[i])) < stringArr.Value(int(indices[j]))
-			}
-			return stringArr.Value(int(indices[i])) > stringArr.Value(int(indices[j]))
-		})
-	default:
-		return nil, fmt.Errorf("sorting not implemented for type %s", input.DataType())
+	indices := make([]int64, input.Len())
+	for i := range indices {
+		indices[i] = int64(i)
 	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return compare(indices[i], indices[j]) < 0
+	})
 
 	// Create an Int64Array from the sorted indices
 	builder := array.NewInt64Builder(memory.DefaultAllocator)
@@ -178,8 +73,60 @@ func SortIndices(ctx context.Context, input arrow.Array, order SortOrder) (arrow
 	return builder.NewArray(), nil
 }
 
-// TakeWithIndices reorders elements of the array according to the indices
-func TakeWithIndices(ctx context.Context, input arrow.Array, indices arrow.Array) (arrow.Array, error) {
+// NegativeIndexPolicy decides how TakeWithIndices treats negative indices
+type NegativeIndexPolicy int
+
+const (
+	// NegativeIndexError rejects negative indices like any out-of-bounds index
+	NegativeIndexError NegativeIndexPolicy = iota
+	// NegativeIndexFromEnd counts negative indices from the end, Python-style,
+	// so -1 is the last element
+	NegativeIndexFromEnd
+	// NegativeIndexNull emits a null for every negative index, for index
+	// arrays that use -1 to mean "no match"
+	NegativeIndexNull
+)
+
+// TakeOptions controls TakeWithIndices
+type TakeOptions struct {
+	// Negative decides how negative indices are treated. The default rejects them.
+	Negative NegativeIndexPolicy
+}
+
+// TakeWithIndices reorders elements of the array according to the indices. By
+// default every index must be in [0, len(input)); a null index yields a null
+// element. Set TakeOptions.Negative to accept negative indices. Under a context
+// from WithAllocator the result is allocated from that allocator.
+func TakeWithIndices(ctx context.Context, input arrow.Array, indices arrow.Array, opts ...TakeOptions) (arrow.Array, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	if len(opts) == 1 && opts[0].Negative != NegativeIndexError {
+		resolved, err := resolveNegativeIndices(ctx, indices, input.Len(), opts[0].Negative)
+		if err != nil {
+			return nil, err
+		}
+		defer resolved.Release()
+		indices = resolved
+	}
+
+	// Types the allocator path cannot copy fall through to the kernel, so
+	// attaching an allocator never changes which inputs are accepted
+	if mem, ok := contextAllocator(ctx); ok && arrow.IsInteger(indices.DataType().ID()) {
+		if _, supported := allocatorTakeWidth(input.DataType()); supported {
+			indicesArr, ok := indices.(*array.Int64)
+			if !ok {
+				widened, err := compute.CastToType(ctx, indices, arrow.PrimitiveTypes.Int64)
+				if err != nil {
+					return nil, fmt.Errorf("failed to widen indices to Int64: %w", err)
+				}
+				defer widened.Release()
+				indicesArr = widened.(*array.Int64)
+			}
+			return takeWithAllocator(mem, input, indicesArr)
+		}
+	}
+
 	result, err := compute.TakeArray(ctx, input, indices)
 	if err == nil {
 		// compute-upgraded
@@ -228,6 +175,10 @@ func TakeWithIndices(ctx context.Context, input arrow.Array, indices arrow.Array
 
 	// Append values according to indices
 	for i := 0; i < length; i++ {
+		if indicesArr.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
 		idx := int(indicesArr.Value(i))
 		if idx < 0 || idx >= input.Len() {
 			return nil, fmt.Errorf("index out of bounds: %d", idx)
@@ -269,22 +220,180 @@ func TakeWithIndices(ctx context.Context, input arrow.Array, indices arrow.Array
 	return builder.NewArray(), nil
 }
 
-// NthElement returns the nth element in sorted order
-func NthElement(ctx context.Context, input arrow.Array, n int64, order SortOrder) (interface{}, error) {
-	// Check if n is in range
-	if n < 0 || n >= int64(input.Len()) {
-		return nil, fmt.Errorf("index %d out of range (0-%d)", n, input.Len()-1)
+// resolveNegativeIndices returns the indices as an Int64 array with negative
+// entries counted from the end of an array of the given length or replaced by
+// nulls, according to the policy
+func resolveNegativeIndices(ctx context.Context, indices arrow.Array, length int, policy NegativeIndexPolicy) (arrow.Array, error) {
+	if policy != NegativeIndexFromEnd && policy != NegativeIndexNull {
+		return nil, fmt.Errorf("unknown negative index policy: %d", policy)
+	}
+	if !arrow.IsInteger(indices.DataType().ID()) {
+		return nil, fmt.Errorf("indices must be integers, got %s", indices.DataType())
 	}
 
-	// Sort the indices
-	indices, err := SortIndices(ctx, input, order)
+	cast, err := Cast(ctx, indices, arrow.PrimitiveTypes.Int64)
 	if err != nil {
 		return nil, err
 	}
-	defer indices.Release()
+	defer cast.Release()
+	values := cast.(*array.Int64)
 
-	// Get the nth index
-	nthIndex := indices.(*array.Int64).Value(int(n))
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(values.Len())
+	for i := 0; i < values.Len(); i++ {
+		idx := values.Value(i)
+		switch {
+		case values.IsNull(i), idx < 0 && policy == NegativeIndexNull:
+			builder.AppendNull()
+		case idx < 0 && idx+int64(length) < 0:
+			return nil, fmt.Errorf("index out of bounds: %d", idx)
+		case idx < 0:
+			builder.Append(idx + int64(length))
+		default:
+			builder.Append(idx)
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// allocatorTakeWidth reports whether takeWithAllocator can copy values of dt,
+// and their byte width when they are fixed-width
+func allocatorTakeWidth(dt arrow.DataType) (int, bool) {
+	switch dt.ID() {
+	case arrow.BOOL, arrow.STRING, arrow.BINARY:
+		return 0, true
+	case arrow.DICTIONARY, arrow.EXTENSION:
+		return 0, false
+	}
+	fixed, ok := dt.(arrow.FixedWidthDataType)
+	if !ok || fixed.BitWidth()%8 != 0 {
+		return 0, false
+	}
+	return fixed.BitWidth() / 8, true
+}
+
+// takeWithAllocator gathers the elements of the input at the indices into
+// buffers allocated from mem on the calling goroutine. A null index yields a
+// null element.
+func takeWithAllocator(mem memory.Allocator, input arrow.Array, indices *array.Int64) (arrow.Array, error) {
+	dt := input.DataType()
+	width, ok := allocatorTakeWidth(dt)
+	if !ok {
+		return nil, fmt.Errorf("take with a context allocator does not support type %s", dt)
+	}
+
+	// Resolve each output element to a source row, or -1 for a null
+	length := indices.Len()
+	rows := make([]int, length)
+	nulls := 0
+	for i := range rows {
+		if indices.IsNull(i) {
+			rows[i] = -1
+			nulls++
+			continue
+		}
+		idx := indices.Value(i)
+		if idx < 0 || idx >= int64(input.Len()) {
+			return nil, fmt.Errorf("index out of bounds: %d", idx)
+		}
+		if input.IsNull(int(idx)) {
+			rows[i] = -1
+			nulls++
+			continue
+		}
+		rows[i] = int(idx)
+	}
+
+	// newBuffer allocates a zeroed buffer that is released once the array
+	// data has taken its own reference, or when an allocation panics
+	var owned []*memory.Buffer
+	defer func() {
+		for _, buf := range owned {
+			buf.Release()
+		}
+	}()
+	newBuffer := func(size int) *memory.Buffer {
+		buf := memory.NewResizableBuffer(mem)
+		owned = append(owned, buf)
+		buf.Resize(size)
+		memory.Set(buf.Bytes(), 0)
+		return buf
+	}
+
+	var validity *memory.Buffer
+	if nulls > 0 {
+		validity = newBuffer(int(bitutil.BytesForBits(int64(length))))
+		for i, row := range rows {
+			if row >= 0 {
+				bitutil.SetBit(validity.Bytes(), i)
+			}
+		}
+	}
+
+	var buffers []*memory.Buffer
+	switch arr := input.(type) {
+	case *array.Boolean:
+		values := newBuffer(int(bitutil.BytesForBits(int64(length))))
+		for i, row := range rows {
+			if row >= 0 && arr.Value(row) {
+				bitutil.SetBit(values.Bytes(), i)
+			}
+		}
+		buffers = []*memory.Buffer{validity, values}
+	case *array.String, *array.Binary:
+		value := func(row int) []byte {
+			if str, ok := arr.(*array.String); ok {
+				return []byte(str.Value(row))
+			}
+			return arr.(*array.Binary).Value(row)
+		}
+		total := 0
+		for _, row := range rows {
+			if row >= 0 {
+				total += len(value(row))
+			}
+		}
+		if total > math.MaxInt32 {
+			return nil, fmt.Errorf("take result of %d bytes exceeds the %s offset range", total, dt)
+		}
+		offsets := newBuffer((length + 1) * arrow.Int32SizeBytes)
+		data := newBuffer(total)
+		offsetValues := arrow.Int32Traits.CastFromBytes(offsets.Bytes())
+		pos := 0
+		for i, row := range rows {
+			if row >= 0 {
+				pos += copy(data.Bytes()[pos:], value(row))
+			}
+			offsetValues[i+1] = int32(pos)
+		}
+		buffers = []*memory.Buffer{validity, offsets, data}
+	default:
+		src := input.Data().Buffers()[1].Bytes()[input.Data().Offset()*width:]
+		values := newBuffer(length * width)
+		dst := values.Bytes()
+		for i, row := range rows {
+			if row >= 0 {
+				copy(dst[i*width:(i+1)*width], src[row*width:(row+1)*width])
+			}
+		}
+		buffers = []*memory.Buffer{validity, values}
+	}
+
+	data := array.NewData(dt, length, buffers, nil, nulls, 0)
+	defer data.Release()
+	return array.MakeFromData(data), nil
+}
+
+// NthElement returns the nth element in sorted order. It uses quickselect, so it
+// runs in linear time on average, and returns the same element a stable sort
+// would place at position n.
+func NthElement(ctx context.Context, input arrow.Array, n int64, order SortOrder) (interface{}, error) {
+	// Find the original position of the nth element
+	nthIndex, err := NthElementIndex(ctx, input, n, order)
+	if err != nil {
+		return nil, err
+	}
 
 	// Handle null values
 	if input.IsNull(int(nthIndex)) {
@@ -322,7 +431,146 @@ func NthElement(ctx context.Context, input arrow.Array, n int64, order SortOrder
 	}
 }
 
-// Rank returns the rank of each element in the array
+// NthElementIndex returns the original position of the element that a stable
+// sort would place at position n. Nulls sort first, as in SortIndices.
+func NthElementIndex(ctx context.Context, input arrow.Array, n int64, order SortOrder) (int64, error) {
+	// Check if n is in range
+	if n < 0 || n >= int64(input.Len()) {
+		return -1, fmt.Errorf("index %d out of range (0-%d)", n, input.Len()-1)
+	}
+
+	compare, err := valueComparator(input, order)
+	if err != nil {
+		return -1, err
+	}
+
+	indices := make([]int64, input.Len())
+	for i := range indices {
+		indices[i] = int64(i)
+	}
+
+	// Break ties by position so the result matches a stable sort
+	less := func(a, b int64) bool {
+		if c := compare(a, b); c != 0 {
+			return c < 0
+		}
+		return a < b
+	}
+	return quickselect(indices, int(n), less), nil
+}
+
+// quickselect partially reorders indices so that position n holds the element
+// that would be there if indices were fully sorted by less, and returns it
+func quickselect(indices []int64, n int, less func(a, b int64) bool) int64 {
+	lo, hi := 0, len(indices)-1
+	for lo < hi {
+		// Partition around the middle element
+		mid := lo + (hi-lo)/2
+		indices[mid], indices[hi] = indices[hi], indices[mid]
+		pivot := indices[hi]
+		store := lo
+		for i := lo; i < hi; i++ {
+			if less(indices[i], pivot) {
+				indices[i], indices[store] = indices[store], indices[i]
+				store++
+			}
+		}
+		indices[store], indices[hi] = indices[hi], indices[store]
+
+		switch {
+		case n == store:
+			return indices[n]
+		case n < store:
+			hi = store - 1
+		default:
+			lo = store + 1
+		}
+	}
+	return indices[n]
+}
+
+// valueComparator returns a function comparing the elements at two positions of
+// the input in the given order, with nulls ordered first
+func valueComparator(input arrow.Array, order SortOrder) (func(a, b int64) int, error) {
+	var compare func(a, b int64) int
+	switch arr := input.(type) {
+	case *array.Boolean:
+		compare = func(a, b int64) int {
+			va, vb := arr.Value(int(a)), arr.Value(int(b))
+			switch {
+			case va == vb:
+				return 0
+			case vb:
+				return -1
+			default:
+				return 1
+			}
+		}
+	case *array.Int8:
+		compare = orderedComparator[int8](arr)
+	case *array.Int16:
+		compare = orderedComparator[int16](arr)
+	case *array.Int32:
+		compare = orderedComparator[int32](arr)
+	case *array.Int64:
+		compare = orderedComparator[int64](arr)
+	case *array.Uint8:
+		compare = orderedComparator[uint8](arr)
+	case *array.Uint16:
+		compare = orderedComparator[uint16](arr)
+	case *array.Uint32:
+		compare = orderedComparator[uint32](arr)
+	case *array.Uint64:
+		compare = orderedComparator[uint64](arr)
+	case *array.Float32:
+		compare = orderedComparator[float32](arr)
+	case *array.Float64:
+		compare = orderedComparator[float64](arr)
+	case *array.String:
+		compare = orderedComparator[string](arr)
+	case *array.Date32:
+		compare = orderedComparator[arrow.Date32](arr)
+	case *array.Date64:
+		compare = orderedComparator[arrow.Date64](arr)
+	case *array.Timestamp:
+		compare = orderedComparator[arrow.Timestamp](arr)
+	case *array.Time32:
+		compare = orderedComparator[arrow.Time32](arr)
+	case *array.Time64:
+		compare = orderedComparator[arrow.Time64](arr)
+	case *array.Duration:
+		compare = orderedComparator[arrow.Duration](arr)
+	default:
+		return nil, fmt.Errorf("sorting not implemented for type %s", input.DataType())
+	}
+
+	return func(a, b int64) int {
+		// Handle nulls - nulls come first regardless of order
+		nullA, nullB := input.IsNull(int(a)), input.IsNull(int(b))
+		switch {
+		case nullA && nullB:
+			return 0
+		case nullA:
+			return -1
+		case nullB:
+			return 1
+		}
+		if order == Descending {
+			return -compare(a, b)
+		}
+		return compare(a, b)
+	}, nil
+}
+
+// orderedComparator returns an ascending comparison of two non-null positions of arr
+func orderedComparator[T cmp.Ordered](arr valuer[T]) func(a, b int64) int {
+	return func(a, b int64) int {
+		return cmp.Compare(arr.Value(int(a)), arr.Value(int(b)))
+	}
+}
+
+// Rank returns the rank of each element in the array. Because SortIndices is
+// stable, tied values are ranked in their input order.
 func Rank(ctx context.Context, input arrow.Array, order SortOrder) (arrow.Array, error) {
 	// Get sort indices
 	sortIndices, err := SortIndices(ctx, input, order)
@@ -352,8 +600,110 @@ func Rank(ctx context.Context, input arrow.Array, order SortOrder) (arrow.Array,
 	return builder.NewArray(), nil
 }
 
-// UniqueValues returns the unique values in the array
-func UniqueValues(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+// RankMethod selects how RankWithMethod ranks tied values
+type RankMethod int
+
+const (
+	// RankOrdinal gives tied values distinct ranks in input order, as Rank does
+	RankOrdinal RankMethod = iota
+	// RankMin gives tied values the lowest rank of their group
+	RankMin
+	// RankMax gives tied values the highest rank of their group
+	RankMax
+	// RankDense gives tied values the same rank and leaves no gaps after ties
+	RankDense
+)
+
+// RankWithMethod returns the zero-based rank of each non-null element among the
+// non-null elements of the array, resolving ties by method. Nulls get a null
+// rank. The result is an Int64 array.
+func RankWithMethod(ctx context.Context, input arrow.Array, order SortOrder, method RankMethod) (arrow.Array, error) {
+	if method < RankOrdinal || method > RankDense {
+		return nil, fmt.Errorf("unknown rank method: %d", method)
+	}
+
+	compare, err := valueComparator(input, order)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]int64, 0, input.Len()-input.NullN())
+	for i := 0; i < input.Len(); i++ {
+		if input.IsValid(i) {
+			indices = append(indices, int64(i))
+		}
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return compare(indices[i], indices[j]) < 0
+	})
+
+	ranks := make([]int64, input.Len())
+	var dense int64
+	for start := 0; start < len(indices); {
+		// Find the end of the group tied with indices[start]
+		end := start + 1
+		for end < len(indices) && compare(indices[start], indices[end]) == 0 {
+			end++
+		}
+		for pos := start; pos < end; pos++ {
+			switch method {
+			case RankOrdinal:
+				ranks[indices[pos]] = int64(pos)
+			case RankMin:
+				ranks[indices[pos]] = int64(start)
+			case RankMax:
+				ranks[indices[pos]] = int64(end - 1)
+			case RankDense:
+				ranks[indices[pos]] = dense
+			}
+		}
+		dense++
+		start = end
+	}
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(input.Len())
+	for i, rank := range ranks {
+		if input.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(rank)
+	}
+	return builder.NewArray(), nil
+}
+
+// UniqueOptions controls how UniqueValues and CountValues group float values
+type UniqueOptions struct {
+	// EqualNaN treats every NaN as the same value, reported once as the
+	// canonical math.NaN() at the position of the first NaN. By default each
+	// NaN is distinct, matching Go map semantics where NaN != NaN.
+	EqualNaN bool
+}
+
+// UniqueValues returns the unique values in the array. Float values come in
+// order of first appearance, with a null at the position of the first null.
+func UniqueValues(ctx context.Context, input arrow.Array, opts ...UniqueOptions) (arrow.Array, error) {
+	options, err := uniqueOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The unique kernel groups NaNs by bit pattern, so float arrays holding
+	// NaNs take the manual path where NaN handling follows the options. Both
+	// keep the order of first appearance.
+	switch arr := input.(type) {
+	case *array.Float32:
+		if containsNaN(arr) {
+			return uniqueFloat(arr, options.EqualNaN, array.NewFloat32Builder(memory.DefaultAllocator)), nil
+		}
+	case *array.Float64:
+		if containsNaN(arr) {
+			return uniqueFloat(arr, options.EqualNaN, array.NewFloat64Builder(memory.DefaultAllocator)), nil
+		}
+	}
+
 	result, err := compute.UniqueArray(ctx, input)
 	if err == nil {
 		// compute-upgraded
@@ -423,41 +773,137 @@ func UniqueValues(ctx context.Context, input arrow.Array) (arrow.Array, error) {
 		builder.AppendValues(uniqueValues, nil)
 
 		return builder.NewArray(), nil
+	case arrow.FLOAT32:
+		return uniqueFloat(input.(*array.Float32), options.EqualNaN, array.NewFloat32Builder(memory.DefaultAllocator)), nil
 	case arrow.FLOAT64:
-		float64Arr := input.(*array.Float64)
-		uniqueMap := make(map[float64]bool)
-		hasNull := false
+		return uniqueFloat(input.(*array.Float64), options.EqualNaN, array.NewFloat64Builder(memory.DefaultAllocator)), nil
+	default:
+		return nil, fmt.Errorf("unique not implemented for type %s", input.DataType())
+	}
+}
 
-		for i := 0; i < float64Arr.Len(); i++ {
-			if float64Arr.IsNull(i) {
-				hasNull = true
-			} else {
-				uniqueMap[float64Arr.Value(i)] = true
-			}
-		}
+// UniqueStable returns the distinct values of the array in order of first
+// appearance, with at most one null at the position of the first null. Unlike
+// UniqueValues, the result is never sorted.
+func UniqueStable(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+	first, err := firstOccurrences(input)
+	if err != nil {
+		return nil, err
+	}
 
-		uniqueValues := make([]float64, 0, len(uniqueMap))
-		for val := range uniqueMap {
-			uniqueValues = append(uniqueValues, val)
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	for i, isFirst := range first {
+		if isFirst {
+			builder.Append(int64(i))
 		}
-		sort.Float64s(uniqueValues)
+	}
+	indices := builder.NewArray()
+	defer indices.Release()
 
-		builder := array.NewFloat64Builder(memory.DefaultAllocator)
-		defer builder.Release()
+	return TakeWithIndices(ctx, input, indices)
+}
 
-		if hasNull {
-			builder.AppendNull()
+// HasDuplicates reports whether any value occurs more than once in the array.
+// Nulls count as equal to each other.
+func HasDuplicates(ctx context.Context, input arrow.Array) (bool, error) {
+	first, err := firstOccurrences(input)
+	if err != nil {
+		return false, err
+	}
+	for _, isFirst := range first {
+		if !isFirst {
+			return true, nil
 		}
-		builder.AppendValues(uniqueValues, nil)
+	}
+	return false, nil
+}
 
-		return builder.NewArray(), nil
+// DuplicatedMask returns a mask marking every occurrence of a value after its
+// first as true, so filtering with it yields the offending duplicates and
+// filtering with its inverse deduplicates. Nulls count as equal to each other.
+func DuplicatedMask(ctx context.Context, input arrow.Array) (*array.Boolean, error) {
+	first, err := firstOccurrences(input)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(len(first))
+	for _, isFirst := range first {
+		builder.Append(!isFirst)
+	}
+	return builder.NewBooleanArray(), nil
+}
+
+// firstOccurrences reports for each element whether it is the first occurrence
+// of its value. All nulls are treated as one value.
+func firstOccurrences(input arrow.Array) ([]bool, error) {
+	switch arr := input.(type) {
+	case *array.Boolean:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Int8:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Int16:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Int32:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Int64:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Uint8:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Uint16:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Uint32:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Uint64:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Float32:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Float64:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.String:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.LargeString:
+		return firstOccurrencesBy(arr, arr.Value), nil
+	case *array.Binary:
+		return firstOccurrencesBy(arr, func(i int) string { return string(arr.Value(i)) }), nil
 	default:
 		return nil, fmt.Errorf("unique not implemented for type %s", input.DataType())
 	}
 }
 
-// CountValues returns the unique values and their counts in the array
-func CountValues(ctx context.Context, input arrow.Array) (values arrow.Array, counts arrow.Array, err error) {
+// firstOccurrencesBy reports for each element whether it is the first
+// occurrence of its key
+func firstOccurrencesBy[K comparable](arr arrow.Array, key func(i int) K) []bool {
+	first := make([]bool, arr.Len())
+	seen := make(map[K]struct{})
+	nullSeen := false
+	for i := range first {
+		if arr.IsNull(i) {
+			first[i] = !nullSeen
+			nullSeen = true
+			continue
+		}
+		k := key(i)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			first[i] = true
+		}
+	}
+	return first
+}
+
+// CountValues returns the unique values and their counts in the array. Float
+// values come in order of first appearance, with a null at the position of the
+// first null.
+func CountValues(ctx context.Context, input arrow.Array, opts ...UniqueOptions) (values arrow.Array, counts arrow.Array, err error) {
+	options, err := uniqueOptions(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Implement value_counts manually
 	switch input.DataType().ID() {
 	case arrow.BOOL:
@@ -538,52 +984,170 @@ func CountValues(ctx context.Context, input arrow.Array) (values arrow.Array, co
 		}
 
 		return valBuilder.NewArray(), countBuilder.NewArray(), nil
+	case arrow.FLOAT32:
+		values, counts := countFloat(input.(*array.Float32), options.EqualNaN, array.NewFloat32Builder(memory.DefaultAllocator))
+		return values, counts, nil
 	case arrow.FLOAT64:
-		float64Arr := input.(*array.Float64)
-		countMap := make(map[float64]int64)
-		nullCount := int64(0)
+		values, counts := countFloat(input.(*array.Float64), options.EqualNaN, array.NewFloat64Builder(memory.DefaultAllocator))
+		return values, counts, nil
+	default:
+		return nil, nil, fmt.Errorf("value_counts not implemented for type %s", input.DataType())
+	}
+}
 
-		for i := 0; i < float64Arr.Len(); i++ {
-			if float64Arr.IsNull(i) {
-				nullCount++
-			} else {
-				countMap[float64Arr.Value(i)]++
+// uniqueOptions resolves the optional UniqueOptions argument
+func uniqueOptions(opts []UniqueOptions) (UniqueOptions, error) {
+	switch len(opts) {
+	case 0:
+		return UniqueOptions{}, nil
+	case 1:
+		return opts[0], nil
+	default:
+		return UniqueOptions{}, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+}
+
+// floatArray is a Float32 or Float64 array
+type floatArray[T float32 | float64] interface {
+	arrow.Array
+	Value(i int) T
+}
+
+// floatBuilder is a Float32 or Float64 builder
+type floatBuilder[T float32 | float64] interface {
+	array.Builder
+	Append(v T)
+}
+
+// containsNaN reports whether any valid value of the array is NaN
+func containsNaN[T float32 | float64](arr floatArray[T]) bool {
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsValid(i) && math.IsNaN(float64(arr.Value(i))) {
+			return true
+		}
+	}
+	return false
+}
+
+// countFloat counts the values of a float array into builder, in order of
+// first appearance. NaNs are never equal as map keys, so they are tracked
+// apart from the other values and either reported once each or folded into
+// one canonical NaN entry. The builder is released.
+func countFloat[T float32 | float64](arr floatArray[T], equalNaN bool, builder floatBuilder[T]) (values arrow.Array, counts arrow.Array) {
+	defer builder.Release()
+	countBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer countBuilder.Release()
+
+	// Each entry is the first row of a distinct value and its count
+	var firstRows, entryCounts []int
+	entryOf := make(map[T]int)
+	nullEntry, nanEntry := -1, -1
+	addTo := func(entry *int, row int) {
+		if *entry < 0 {
+			*entry = len(firstRows)
+			firstRows = append(firstRows, row)
+			entryCounts = append(entryCounts, 0)
+		}
+		entryCounts[*entry]++
+	}
+	for i := 0; i < arr.Len(); i++ {
+		switch {
+		case arr.IsNull(i):
+			addTo(&nullEntry, i)
+		case math.IsNaN(float64(arr.Value(i))):
+			if equalNaN {
+				addTo(&nanEntry, i)
+				continue
 			}
+			entry := -1
+			addTo(&entry, i)
+		default:
+			v := arr.Value(i)
+			entry, ok := entryOf[v]
+			if !ok {
+				entry = -1
+			}
+			addTo(&entry, i)
+			entryOf[v] = entry
 		}
+	}
 
-		// Extract unique values and their counts
-		uniqueValues := make([]float64, 0, len(countMap))
-		for val := range countMap {
-			uniqueValues = append(uniqueValues, val)
+	builder.Reserve(len(firstRows))
+	countBuilder.Reserve(len(firstRows))
+	for entry, row := range firstRows {
+		switch {
+		case entry == nullEntry:
+			builder.AppendNull()
+		case entry == nanEntry:
+			builder.Append(T(math.NaN()))
+		default:
+			builder.Append(arr.Value(row))
 		}
-		sort.Float64s(uniqueValues)
+		countBuilder.Append(int64(entryCounts[entry]))
+	}
+	return builder.NewArray(), countBuilder.NewArray()
+}
 
-		// Build values array
-		valBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
-		defer valBuilder.Release()
+// uniqueFloat returns the unique values of a float array, in order of first
+// appearance
+func uniqueFloat[T float32 | float64](arr floatArray[T], equalNaN bool, builder floatBuilder[T]) arrow.Array {
+	values, counts := countFloat(arr, equalNaN, builder)
+	counts.Release()
+	return values
+}
 
-		// Build counts array
-		countBuilder := array.NewInt64Builder(memory.DefaultAllocator)
-		defer countBuilder.Release()
+// MonotonicOptions controls how IsMonotonic treats nulls
+type MonotonicOptions struct {
+	// NullsBreakOrder makes any null mark the array as neither increasing nor
+	// decreasing. By default nulls are skipped.
+	NullsBreakOrder bool
+}
 
-		if nullCount > 0 {
-			valBuilder.AppendNull()
-			countBuilder.Append(nullCount)
+// IsMonotonic reports whether the array is monotonically non-decreasing and/or
+// non-increasing in a single pass. Empty arrays and arrays with one value are
+// both. Numeric, string, boolean and temporal arrays are supported.
+func IsMonotonic(ctx context.Context, input arrow.Array, opts ...MonotonicOptions) (increasing bool, decreasing bool, err error) {
+	if len(opts) > 1 {
+		return false, false, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	var options MonotonicOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+
+	compare, err := valueComparator(input, Ascending)
+	if err != nil {
+		return false, false, err
+	}
+	if options.NullsBreakOrder && input.NullN() > 0 {
+		return false, false, nil
+	}
+
+	increasing, decreasing = true, true
+	prev := int64(-1)
+	for i := 0; i < input.Len() && (increasing || decreasing); i++ {
+		if input.IsNull(i) {
+			continue
 		}
-		for _, val := range uniqueValues {
-			valBuilder.Append(val)
-			countBuilder.Append(countMap[val])
+		if prev >= 0 {
+			switch c := compare(prev, int64(i)); {
+			case c < 0:
+				decreasing = false
+			case c > 0:
+				increasing = false
+			}
 		}
-
-		return valBuilder.NewArray(), countBuilder.NewArray(), nil
-	default:
-		return nil, nil, fmt.Errorf("value_counts not implemented for type %s", input.DataType())
+		prev = int64(i)
 	}
+	return increasing, decreasing, nil
 }
 
 // RECORD OPERATIONS
 
-// SortRecord sorts a record by one or more columns
+// SortRecord sorts a record by one or more columns, each in its own order,
+// comparing later columns only when earlier ones are equal. The sort is
+// stable: rows equal on every sort column keep their input order, so columns
+// outside the sort key retain their relative order within tied groups.
 func SortRecord(ctx context.Context, input arrow.Record, sortCols []string, sortOrders []SortOrder) (arrow.Record, error) {
 	if len(sortCols) == 0 {
 		return nil, fmt.Errorf("no sort columns specified")
@@ -594,51 +1158,177 @@ func SortRecord(ctx context.Context, input arrow.Record, sortCols []string, sort
 			len(sortCols), len(sortOrders))
 	}
 
-	// For now we'll implement a simpler version using just the first sort column
-	// Multi-column sorting can be added later with more complex logic
-	colName := sortCols[0]
-	order := sortOrders[0]
+	indices, err := sortIndicesByColumns(input, sortCols, sortOrders)
+	if err != nil {
+		return nil, err
+	}
+	defer indices.Release()
+
+	// Create new record with sorted columns
+	return TakeRecord(ctx, input, indices)
+}
 
-	// Get column by name
+// RankColumn returns a new record with a colName_rank column appended, holding
+// the rank of each row's value in the named column as computed by
+// RankWithMethod
+func RankColumn(ctx context.Context, input arrow.Record, colName string, order SortOrder, method RankMethod) (arrow.Record, error) {
 	col, err := GetColumn(input, colName)
 	if err != nil {
 		return nil, err
 	}
 	defer ReleaseArray(col)
 
-	// Get sort indices
-	indices, err := SortIndices(ctx, col, order)
+	ranks, err := RankWithMethod(ctx, col, order, method)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", colName, err)
+	}
+	defer ranks.Release()
+
+	field := arrow.Field{Name: colName + "_rank", Type: arrow.PrimitiveTypes.Int64, Nullable: true}
+	return AppendColumn(input, field, ranks)
+}
+
+// SortRecordByColumns2 sorts a record by a primary column, breaking ties with a
+// secondary column. Rows equal on both columns keep their input order.
+func SortRecordByColumns2(ctx context.Context, input arrow.Record, primary string, primaryOrder SortOrder, secondary string, secondaryOrder SortOrder) (arrow.Record, error) {
+	indices, err := sortIndicesByColumns(input, []string{primary, secondary}, []SortOrder{primaryOrder, secondaryOrder})
 	if err != nil {
-		ReleaseArray(col)
 		return nil, err
 	}
 	defer indices.Release()
 
-	// Create new record with sorted columns
+	return TakeRecord(ctx, input, indices)
+}
+
+// sortIndicesByColumns returns the indices that stably sort the record by the
+// given columns, comparing later columns only when earlier ones are equal
+func sortIndicesByColumns(input arrow.Record, sortCols []string, sortOrders []SortOrder) (arrow.Array, error) {
+	comparators := make([]func(a, b int64) int, len(sortCols))
+	for i, colName := range sortCols {
+		colIndex, err := GetColumnIndex(input, colName)
+		if err != nil {
+			return nil, err
+		}
+		compare, err := valueComparator(input.Column(colIndex), sortOrders[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", colName, err)
+		}
+		comparators[i] = compare
+	}
+
+	indices := make([]int64, input.NumRows())
+	for i := range indices {
+		indices[i] = int64(i)
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		for _, compare := range comparators {
+			if c := compare(indices[i], indices[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues(indices, nil)
+	return builder.NewArray(), nil
+}
+
+// TakeRecord returns a new record with the rows of the input reordered according to the indices.
+// Under a context from WithAllocator the columns are allocated from that allocator.
+func TakeRecord(ctx context.Context, input arrow.Record, indices arrow.Array) (arrow.Record, error) {
+	// Release the columns on every path, including an allocator panic; the
+	// record retains its own references
 	cols := make([]arrow.Array, input.NumCols())
+	defer func() {
+		ReleaseArrays(cols...)
+	}()
 	for i := 0; i < int(input.NumCols()); i++ {
 		col := input.Column(i)
-		sorted, err := TakeWithIndices(ctx, col, indices)
+		taken, err := TakeWithIndices(ctx, col, indices)
 		if err != nil {
-			// Clean up already created columns
-			for j := 0; j < i; j++ {
-				cols[j].Release()
-			}
-			return nil, fmt.Errorf("error sorting column %d: %w", i, err)
+			return nil, fmt.Errorf("error taking column %d: %w", i, err)
 		}
-		cols[i] = sorted
+		cols[i] = taken
 	}
 
-	// Create new record
-	schema := input.Schema()
-	result_record := array.NewRecord(schema, cols, int64(cols[0].Len()))
+	return array.NewRecord(input.Schema(), cols, int64(indices.Len())), nil
+}
+
+// MergeOptions controls MergeSorted
+type MergeOptions struct {
+	// Order is the order both inputs are sorted in, with nulls first
+	Order SortOrder
+	// TrustSorted skips checking that the inputs are sorted. Merging unsorted
+	// inputs produces an unsorted result.
+	TrustSorted bool
+}
 
-	// Release the columns (record takes ownership)
-	for _, col := range cols {
-		col.Release()
+// MergeSorted merges two records with identical schemas that are already sorted
+// by keyCol into one sorted record in a single O(n+m) pass. The merge is
+// stable: on equal keys, rows of a come before rows of b. Unless TrustSorted is
+// set, both inputs are checked and an unsorted input is an error.
+func MergeSorted(ctx context.Context, a, b arrow.Record, keyCol string, opts ...MergeOptions) (arrow.Record, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
 	}
+	var options MergeOptions
+	if len(opts) == 1 {
+		options = opts[0]
+	}
+
+	mem, _ := contextAllocator(ctx)
+	combined, err := concatRecords(mem, []arrow.Record{a, b})
+	if err != nil {
+		return nil, err
+	}
+	defer combined.Release()
+
+	keyIndex, err := GetColumnIndex(combined, keyCol)
+	if err != nil {
+		return nil, err
+	}
+	compare, err := valueComparator(combined.Column(keyIndex), options.Order)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", keyCol, err)
+	}
+
+	// Rows of a are [0, split) and rows of b are [split, total)
+	split, total := a.NumRows(), combined.NumRows()
+	if !options.TrustSorted {
+		for i := int64(1); i < total; i++ {
+			if i != split && compare(i-1, i) > 0 {
+				return nil, fmt.Errorf("input is not sorted by %s at row %d of the combined input", keyCol, i)
+			}
+		}
+	}
+
+	indices := make([]int64, 0, total)
+	i, j := int64(0), split
+	for i < split && j < total {
+		if compare(j, i) < 0 {
+			indices = append(indices, j)
+			j++
+		} else {
+			indices = append(indices, i)
+			i++
+		}
+	}
+	for ; i < split; i++ {
+		indices = append(indices, i)
+	}
+	for ; j < total; j++ {
+		indices = append(indices, j)
+	}
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues(indices, nil)
+	indicesArr := builder.NewArray()
+	defer indicesArr.Release()
 
-	return result_record, nil
+	return TakeRecord(ctx, combined, indicesArr)
 }
 
 // SortRecordByColumn sorts a record by a single column
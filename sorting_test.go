@@ -3,8 +3,10 @@ package archery_test
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
@@ -105,6 +107,42 @@ func Example_sortIndices() {
 	// 2 4 1 3 0
 }
 
+func Example_sortTemporal() {
+	// Create a timestamp array with a null
+	tsType := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
+	builder := array.NewTimestampBuilder(memory.DefaultAllocator, tsType)
+	defer builder.Release()
+	builder.AppendValues([]arrow.Timestamp{1700000300, 1700000100, 0, 1700000200}, []bool{true, true, false, true})
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Sort and get the sort indices of the timestamps
+	ctx := context.Background()
+	sorted, err := archery.Sort(ctx, arr, archery.Descending)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer sorted.Release()
+
+	indices, err := archery.SortIndices(ctx, arr, archery.Ascending)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer indices.Release()
+
+	// Print the results
+	fmt.Println("Type:", sorted.DataType())
+	fmt.Println("Sorted:", sorted)
+	fmt.Println("Ascending indices:", indices)
+
+	// Output:
+	// Type: timestamp[s, tz=UTC]
+	// Sorted: [(null) 1700000300 1700000200 1700000100]
+	// Ascending indices: [2 1 3 0]
+}
+
 func Example_uniqueValues() {
 	// Create a test array with duplicates
 	builder := array.NewInt64Builder(memory.DefaultAllocator)
@@ -170,6 +208,62 @@ func Example_countValues() {
 	// 5: 2
 }
 
+func Example_countValuesEqualNaN() {
+	// Sensor readings where NaN marks a failed read
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1.5, math.NaN(), 2.5, math.NaN(), 1.5, math.NaN()}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	ctx := context.Background()
+	for _, equalNaN := range []bool{false, true} {
+		values, counts, err := archery.CountValues(ctx, arr, archery.UniqueOptions{EqualNaN: equalNaN})
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		fmt.Printf("EqualNaN=%v:", equalNaN)
+		for i := 0; i < values.Len(); i++ {
+			fmt.Printf(" %v=%d", values.(*array.Float64).Value(i), counts.(*array.Int64).Value(i))
+		}
+		fmt.Println()
+		archery.ReleaseArrays(values, counts)
+	}
+
+	// Float32 readings follow the option too, and UniqueValues keeps the same
+	// order of first appearance with or without NaNs
+	builder32 := array.NewFloat32Builder(memory.DefaultAllocator)
+	defer builder32.Release()
+	builder32.AppendValues([]float32{2.5, float32(math.NaN()), 1.5, 0, float32(math.NaN())}, []bool{true, true, true, false, true})
+	arr32 := builder32.NewFloat32Array()
+	defer arr32.Release()
+	builder32.AppendValues([]float32{2.5, 1.5, 0, 2.5}, []bool{true, true, false, true})
+	noNaN := builder32.NewFloat32Array()
+	defer noNaN.Release()
+
+	for _, input := range []arrow.Array{arr32, noNaN} {
+		for _, equalNaN := range []bool{false, true} {
+			unique, err := archery.UniqueValues(ctx, input, archery.UniqueOptions{EqualNaN: equalNaN})
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			fmt.Printf("Unique EqualNaN=%v: %v\n", equalNaN, unique)
+			unique.Release()
+		}
+	}
+
+	// Output:
+	// EqualNaN=false: 1.5=2 NaN=1 2.5=1 NaN=1 NaN=1
+	// EqualNaN=true: 1.5=2 NaN=3 2.5=1
+	// Unique EqualNaN=false: [2.5 NaN 1.5 (null) NaN]
+	// Unique EqualNaN=true: [2.5 NaN 1.5 (null)]
+	// Unique EqualNaN=false: [2.5 1.5 (null)]
+	// Unique EqualNaN=true: [2.5 1.5 (null)]
+}
+
 func Example_nthElement() {
 	// Create a test array
 	builder := array.NewFloat64Builder(memory.DefaultAllocator)
@@ -192,3 +286,402 @@ func Example_nthElement() {
 	// Output:
 	// 3rd smallest element: 3.0
 }
+
+func Example_sortIndicesStable() {
+	// Create a test array with repeated keys and nulls
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{2, 1, 0, 2, 1, 0, 1}, []bool{true, true, false, true, true, false, true})
+	arr := builder.NewInt64Array()
+	defer arr.Release()
+
+	// Equal keys keep their input order in both directions
+	ctx := context.Background()
+	for _, order := range []archery.SortOrder{archery.Ascending, archery.Descending} {
+		indices, err := archery.SortIndices(ctx, arr, order)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Println(indices)
+		indices.Release()
+	}
+
+	// Output:
+	// [2 5 1 4 6 0 3]
+	// [2 5 0 3 1 4 6]
+}
+
+func Example_nthElementIndex() {
+	// Create a test array
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{5, 3, 1, 4, 2}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Find where the median lives in the original array
+	ctx := context.Background()
+	index, err := archery.NthElementIndex(ctx, arr, 2, archery.Ascending)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Print the result
+	fmt.Printf("Median %.1f is at index %d\n", arr.Value(int(index)), index)
+
+	// Output:
+	// Median 3.0 is at index 1
+}
+
+func Example_sortRecordByColumns2() {
+	// Create a test record of transactions
+	dateBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer dateBuilder.Release()
+	dateBuilder.AppendValues([]string{"2024-01-02", "2024-01-01", "2024-01-02", "2024-01-01"}, nil)
+	dates := dateBuilder.NewArray()
+	defer dates.Release()
+
+	idBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idBuilder.Release()
+	idBuilder.AppendValues([]int64{7, 9, 3, 4}, nil)
+	ids := idBuilder.NewArray()
+	defer ids.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "date", Type: arrow.BinaryTypes.String},
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{dates, ids}, 4)
+	defer rec.Release()
+
+	// Sort by date, then by id
+	ctx := context.Background()
+	sorted, err := archery.SortRecordByColumns2(ctx, rec, "date", archery.Ascending, "id", archery.Ascending)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(sorted)
+
+	// Print the sorted ids
+	fmt.Println("IDs:", sorted.Column(1))
+
+	// Output:
+	// IDs: [4 9 3 7]
+}
+
+func Example_sortWithIndices() {
+	// Create parallel score and name arrays
+	scoreBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer scoreBuilder.Release()
+	scoreBuilder.AppendValues([]float64{7.5, 9.0, 6.0}, nil)
+	scores := scoreBuilder.NewFloat64Array()
+	defer scores.Release()
+
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"alice", "bob", "carol"}, nil)
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	// Sort the scores and keep the permutation
+	ctx := context.Background()
+	sorted, indices, err := archery.SortWithIndices(ctx, scores, archery.Descending)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(sorted)
+	defer archery.ReleaseArray(indices)
+
+	// Reorder the names the same way
+	sortedNames, err := archery.TakeWithIndices(ctx, names, indices)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(sortedNames)
+
+	// Print the results
+	fmt.Println(sorted)
+	fmt.Println(sortedNames)
+
+	// Output:
+	// [9 7.5 6]
+	// ["bob" "alice" "carol"]
+}
+
+func Example_isMonotonic() {
+	// Create a timestamp column with a gap
+	dt := &arrow.TimestampType{Unit: arrow.Second}
+	builder := array.NewTimestampBuilder(memory.DefaultAllocator, dt)
+	defer builder.Release()
+	builder.AppendValues([]arrow.Timestamp{100, 100, 0, 250}, []bool{true, true, false, true})
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Check whether it is already sorted
+	ctx := context.Background()
+	increasing, decreasing, err := archery.IsMonotonic(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Skipping nulls:", increasing, decreasing)
+
+	// Treat the null as breaking the order
+	increasing, decreasing, err = archery.IsMonotonic(ctx, arr, archery.MonotonicOptions{NullsBreakOrder: true})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Nulls break order:", increasing, decreasing)
+
+	// Output:
+	// Skipping nulls: true false
+	// Nulls break order: false false
+}
+
+func Example_mergeSorted() {
+	// Create a sorted historical record and a sorted batch of new data
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ts", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "source", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	newRecord := func(ts []int64, source string) arrow.Record {
+		tsBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer tsBuilder.Release()
+		tsBuilder.AppendValues(ts, nil)
+		tsCol := tsBuilder.NewArray()
+		defer tsCol.Release()
+
+		sourceBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+		defer sourceBuilder.Release()
+		for range ts {
+			sourceBuilder.Append(source)
+		}
+		sourceCol := sourceBuilder.NewArray()
+		defer sourceCol.Release()
+
+		return array.NewRecord(schema, []arrow.Array{tsCol, sourceCol}, int64(len(ts)))
+	}
+
+	history := newRecord([]int64{1, 4, 6}, "old")
+	defer history.Release()
+	batch := newRecord([]int64{2, 4, 9}, "new")
+	defer batch.Release()
+
+	// Merge without re-sorting
+	ctx := context.Background()
+	merged, err := archery.MergeSorted(ctx, history, batch, "ts")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseRecord(merged)
+
+	// Print the merged columns
+	fmt.Println(merged.Column(0))
+	fmt.Println(merged.Column(1))
+
+	// Output:
+	// [1 2 4 4 6 9]
+	// ["old" "new" "old" "new" "old" "new"]
+}
+
+func Example_uniqueStable() {
+	// Create a column of category labels
+	builder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]string{"cherry", "apple", "", "cherry", "banana", "apple", ""}, []bool{true, true, false, true, true, true, false})
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	// Extract the labels in the order they first occur
+	ctx := context.Background()
+	labels, err := archery.UniqueStable(ctx, arr)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(labels)
+
+	// Print the result
+	fmt.Println(labels)
+
+	// Output:
+	// ["cherry" "apple" (null) "banana"]
+}
+
+func Example_duplicatedMask() {
+	// Create an id column that should be unique
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{101, 102, 101, 103, 102, 101}, nil)
+	ids := builder.NewArray()
+	defer ids.Release()
+
+	// Validate uniqueness
+	ctx := context.Background()
+	dup, err := archery.HasDuplicates(ctx, ids)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Has duplicates:", dup)
+
+	// Flag every repeat after the first occurrence
+	mask, err := archery.DuplicatedMask(ctx, ids)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer mask.Release()
+	fmt.Println("Duplicated:", mask)
+
+	// Output:
+	// Has duplicates: true
+	// Duplicated: [false false true false true true]
+}
+
+func Example_sortRecordStable() {
+	// Create a record of orders in insertion order
+	regionBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer regionBuilder.Release()
+	regionBuilder.AppendValues([]string{"west", "east", "west", "east", "east"}, nil)
+	regions := regionBuilder.NewArray()
+	defer regions.Release()
+
+	amountBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer amountBuilder.Release()
+	amountBuilder.AppendValues([]int64{5, 9, 5, 9, 1}, nil)
+	amounts := amountBuilder.NewArray()
+	defer amounts.Release()
+
+	orderBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer orderBuilder.Release()
+	orderBuilder.AppendValues([]int64{101, 102, 103, 104, 105}, nil)
+	orders := orderBuilder.NewArray()
+	defer orders.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "region", Type: arrow.BinaryTypes.String},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "order_id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{regions, amounts, orders}, 5)
+	defer rec.Release()
+
+	// Sort by region ascending, then amount descending
+	ctx := context.Background()
+	sorted, err := archery.SortRecord(ctx, rec, []string{"region", "amount"}, []archery.SortOrder{archery.Ascending, archery.Descending})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer sorted.Release()
+
+	// Order IDs keep insertion order within tied keys
+	fmt.Println("Region:", sorted.Column(0))
+	fmt.Println("Amount:", sorted.Column(1))
+	fmt.Println("Order:", sorted.Column(2))
+
+	// Output:
+	// Region: ["east" "east" "east" "west" "west"]
+	// Amount: [9 9 1 5 5]
+	// Order: [102 104 105 101 103]
+}
+
+func Example_rankColumn() {
+	// Create a record of salespeople and their revenue
+	nameBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer nameBuilder.Release()
+	nameBuilder.AppendValues([]string{"ana", "ben", "cal", "dot", "eli"}, nil)
+	names := nameBuilder.NewArray()
+	defer names.Release()
+
+	revenueBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer revenueBuilder.Release()
+	revenueBuilder.AppendValues([]int64{500, 700, 500, 0, 300}, []bool{true, true, true, false, true})
+	revenue := revenueBuilder.NewArray()
+	defer revenue.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "revenue", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{names, revenue}, 5)
+	defer rec.Release()
+
+	// Rank by revenue, highest first, with ties sharing a rank
+	ctx := context.Background()
+	ranked, err := archery.RankColumn(ctx, rec, "revenue", archery.Descending, archery.RankMin)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer ranked.Release()
+
+	dense, err := archery.RankWithMethod(ctx, revenue, archery.Descending, archery.RankDense)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer dense.Release()
+
+	fmt.Println("Columns:", archery.ColumnNames(ranked))
+	fmt.Println("Min rank:", ranked.Column(2))
+	fmt.Println("Dense rank:", dense)
+
+	// Output:
+	// Columns: [name revenue revenue_rank]
+	// Min rank: [1 0 1 (null) 3]
+	// Dense rank: [1 0 1 (null) 2]
+}
+
+func Example_takeWithIndicesNegative() {
+	// Create a test array
+	valBuilder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer valBuilder.Release()
+	valBuilder.AppendValues([]string{"a", "b", "c"}, nil)
+	values := valBuilder.NewArray()
+	defer values.Release()
+
+	// Indices from a lookup, where -1 means no match and one entry is null
+	idxBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer idxBuilder.Release()
+	idxBuilder.AppendValues([]int64{2, -1, 0, 0}, []bool{true, true, true, false})
+	indices := idxBuilder.NewArray()
+	defer indices.Release()
+
+	// By default negative indices are out of bounds
+	ctx := context.Background()
+	_, err := archery.TakeWithIndices(ctx, values, indices)
+	fmt.Println("Strict error:", err != nil)
+
+	// Emit nulls for -1
+	taken, err := archery.TakeWithIndices(ctx, values, indices, archery.TakeOptions{Negative: archery.NegativeIndexNull})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer taken.Release()
+	fmt.Println("As null:", taken)
+
+	// Or count from the end, Python-style
+	fromEnd, err := archery.TakeWithIndices(ctx, values, indices, archery.TakeOptions{Negative: archery.NegativeIndexFromEnd})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer fromEnd.Release()
+	fmt.Println("From end:", fromEnd)
+
+	// Output:
+	// Strict error: true
+	// As null: ["c" (null) "a" (null)]
+	// From end: ["c" "c" "a" (null)]
+}
@@ -0,0 +1,178 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// STREAMING QUANTILES
+
+// DefaultTDigestCompression is the compression NewTDigest uses when given a
+// non-positive value
+const DefaultTDigestCompression = 100
+
+// TDigest approximates quantiles of a stream of values in bounded memory. It
+// keeps a sorted list of weighted centroids, merging nearby values while
+// keeping centroids near the tails small, so extreme quantiles such as p99
+// stay accurate.
+//
+// The compression parameter trades memory for accuracy: a digest keeps on the
+// order of compression centroids, and the error of a quantile shrinks roughly
+// in proportion to 1/compression, most of all near 0 and 1. The default of 100
+// typically gives errors well under 1% of the rank at a few kilobytes of
+// memory; use 200 to 500 where tails must be tighter.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []float64
+	total       float64
+	min, max    float64
+}
+
+// centroid is the mean of weight merged values
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest returns an empty digest with the given compression, or
+// DefaultTDigestCompression when compression is not positive
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultTDigestCompression
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds a value to the digest. NaN values are ignored.
+func (d *TDigest) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	d.buffer = append(d.buffer, v)
+	d.min = math.Min(d.min, v)
+	d.max = math.Max(d.max, v)
+	if len(d.buffer) >= int(5*d.compression) {
+		d.compress()
+	}
+}
+
+// Count returns the number of values added
+func (d *TDigest) Count() int64 {
+	return int64(d.total) + int64(len(d.buffer))
+}
+
+// Quantile returns the approximate q-th quantile, q in [0, 1], interpolating
+// between centroids. It returns NaN for an empty digest or q outside [0, 1].
+func (d *TDigest) Quantile(q float64) float64 {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	d.compress()
+	if len(d.centroids) == 0 {
+		return math.NaN()
+	}
+	if len(d.centroids) == 1 {
+		return d.centroids[0].mean
+	}
+
+	// Centroid i is centred at rank cum + weight/2; interpolate between the
+	// centres around the target rank, using min and max at the ends
+	target := q * d.total
+	first := d.centroids[0]
+	if target < first.weight/2 {
+		return d.min + (first.mean-d.min)*target/(first.weight/2)
+	}
+	var cum float64
+	for i := 0; i < len(d.centroids)-1; i++ {
+		c, next := d.centroids[i], d.centroids[i+1]
+		left := cum + c.weight/2
+		right := cum + c.weight + next.weight/2
+		if target <= right {
+			return c.mean + (next.mean-c.mean)*(target-left)/(right-left)
+		}
+		cum += c.weight
+	}
+	last := d.centroids[len(d.centroids)-1]
+	return last.mean + (d.max-last.mean)*(target-(d.total-last.weight/2))/(last.weight/2)
+}
+
+// compress merges the buffered values into the centroids. A centroid at
+// quantile q may hold at most 4*total*q*(1-q)/compression values.
+func (d *TDigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := make([]centroid, 0, len(d.centroids)+len(d.buffer))
+	all = append(all, d.centroids...)
+	for _, v := range d.buffer {
+		all = append(all, centroid{mean: v, weight: 1})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	d.total += float64(len(d.buffer))
+	d.buffer = d.buffer[:0]
+
+	merged := all[:1]
+	var before float64
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+		combined := last.weight + c.weight
+		q := (before + combined/2) / d.total
+		if combined <= 4*d.total*q*(1-q)/d.compression {
+			last.mean += (c.mean - last.mean) * c.weight / combined
+			last.weight = combined
+			continue
+		}
+		before += last.weight
+		merged = append(merged, c)
+	}
+	d.centroids = merged
+}
+
+// QuantileReader returns the approximate q-th quantile, q in [0, 1], of the
+// named column over every record of the reader, using a TDigest with
+// DefaultTDigestCompression so memory stays bounded however long the stream
+// is. Nulls and NaNs are ignored and a column with no values yields NaN, as in
+// Quantile. The reader is consumed but not released.
+func QuantileReader(ctx context.Context, reader array.RecordReader, colName string, q float64) (float64, error) {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return 0, fmt.Errorf("quantile must be in [0, 1], got %v", q)
+	}
+
+	digest := NewTDigest(DefaultTDigestCompression)
+	for reader.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		rec := reader.Record()
+		idx, err := GetColumnIndex(rec, colName)
+		if err != nil {
+			return 0, err
+		}
+		values, err := castFloat64(ctx, rec.Column(idx))
+		if err != nil {
+			return 0, fmt.Errorf("quantile reader: %w", err)
+		}
+		for i := 0; i < values.Len(); i++ {
+			if values.IsValid(i) {
+				digest.Add(values.Value(i))
+			}
+		}
+		values.Release()
+	}
+	if err := reader.Err(); err != nil {
+		return 0, err
+	}
+
+	// An empty digest has no quantile and yields NaN
+	return digest.Quantile(q), nil
+}
@@ -0,0 +1,83 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_tDigest() {
+	// Feed a stream of values one at a time
+	digest := archery.NewTDigest(archery.DefaultTDigestCompression)
+	for i := 1; i <= 1000; i++ {
+		digest.Add(float64(i))
+	}
+
+	fmt.Println("Count:", digest.Count())
+	fmt.Println("Median:", digest.Quantile(0.5))
+	fmt.Println("p99:", digest.Quantile(0.99))
+
+	// Output:
+	// Count: 1000
+	// Median: 500.5
+	// p99: 990.5
+}
+
+func Example_quantileReader() {
+	// Latencies arriving in two batches
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "latency_ms", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	}, nil)
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+
+	builder.AppendValues([]int64{12, 15, 11, 0}, []bool{true, true, true, false})
+	first := builder.NewArray()
+	defer first.Release()
+	builder.AppendValues([]int64{14, 13, 250}, nil)
+	second := builder.NewArray()
+	defer second.Release()
+
+	batch1 := array.NewRecord(schema, []arrow.Array{first}, 4)
+	defer batch1.Release()
+	batch2 := array.NewRecord(schema, []arrow.Array{second}, 3)
+	defer batch2.Release()
+
+	reader, err := array.NewRecordReader(schema, []arrow.Record{batch1, batch2})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer reader.Release()
+
+	// Only a digest of the values is kept between batches
+	ctx := context.Background()
+	median, err := archery.QuantileReader(ctx, reader, "latency_ms", 0.5)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Median latency:", median)
+
+	// A stream without values has no median
+	empty, err := array.NewRecordReader(schema, nil)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer empty.Release()
+	none, err := archery.QuantileReader(ctx, empty, "latency_ms", 0.5)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Median of no latencies:", none)
+
+	// Output:
+	// Median latency: 13.5
+	// Median of no latencies: NaN
+}
@@ -0,0 +1,366 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// VECTOR OPERATIONS
+
+// MeanVector returns the element-wise mean of the rows of a fixed-size-list
+// column with a numeric value type, such as a FixedSizeList<float32> of
+// embeddings, giving their centroid. Null rows are skipped; null elements
+// inside a non-null row are an error. A column with no non-null rows is an
+// error.
+func MeanVector(ctx context.Context, input arrow.Array) ([]float64, error) {
+	list, dim, err := vectorColumn(input)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make([]float64, dim)
+	row := make([]float64, dim)
+	count := 0
+	for i := 0; i < list.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if list.IsNull(i) {
+			continue
+		}
+		if err := vectorAt(list, i, row); err != nil {
+			return nil, err
+		}
+		for j, v := range row {
+			sums[j] += v
+		}
+		count++
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("mean vector: no non-null rows")
+	}
+
+	for j := range sums {
+		sums[j] /= float64(count)
+	}
+	return sums, nil
+}
+
+// NormalizeL2 scales each row of a fixed-size-list column of float32 or
+// float64 values to unit Euclidean length and returns an array of the same
+// type. Null rows stay null and all-zero rows are left as zeros.
+func NormalizeL2(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+	list, dim, err := vectorColumn(input)
+	if err != nil {
+		return nil, err
+	}
+	listType := list.DataType().(*arrow.FixedSizeListType)
+
+	builder := array.NewFixedSizeListBuilderWithField(memory.DefaultAllocator, listType.Len(), listType.ElemField())
+	defer builder.Release()
+	builder.Reserve(list.Len())
+
+	// appendValues writes one row of the result to the value builder
+	var appendValues func(row []float64)
+	switch values := builder.ValueBuilder().(type) {
+	case *array.Float32Builder:
+		appendValues = func(row []float64) {
+			for _, v := range row {
+				values.Append(float32(v))
+			}
+		}
+	case *array.Float64Builder:
+		appendValues = func(row []float64) {
+			values.AppendValues(row, nil)
+		}
+	default:
+		return nil, fmt.Errorf("normalize requires float32 or float64 values, got %s", listType.Elem())
+	}
+
+	row := make([]float64, dim)
+	for i := 0; i < list.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if list.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		if err := vectorAt(list, i, row); err != nil {
+			return nil, err
+		}
+		if norm := l2Norm(row); norm > 0 {
+			for j := range row {
+				row[j] /= norm
+			}
+		}
+		builder.Append(true)
+		appendValues(row)
+	}
+	return builder.NewArray(), nil
+}
+
+// DotProduct returns the row-wise dot product of two fixed-size-list columns
+// of equal length and dimension as a Float64 array. A row is null when either
+// input row is null.
+func DotProduct(ctx context.Context, a, b arrow.Array) (arrow.Array, error) {
+	return pairwiseRows(ctx, a, b, func(x, y []float64) (float64, bool) {
+		return dot(x, y), true
+	})
+}
+
+// CosineSimilarity returns the row-wise cosine similarity of two
+// fixed-size-list columns of equal length and dimension as a Float64 array. A
+// row is null when either input row is null or has zero length, since the
+// angle is then undefined.
+func CosineSimilarity(ctx context.Context, a, b arrow.Array) (arrow.Array, error) {
+	return pairwiseRows(ctx, a, b, func(x, y []float64) (float64, bool) {
+		normX, normY := l2Norm(x), l2Norm(y)
+		if normX == 0 || normY == 0 {
+			return 0, false
+		}
+		return dot(x, y) / (normX * normY), true
+	})
+}
+
+// pairwiseRows applies fn to each pair of non-null rows of a and b, building a
+// Float64 array that is null where either row is null or fn reports no result
+func pairwiseRows(ctx context.Context, a, b arrow.Array, fn func(x, y []float64) (float64, bool)) (arrow.Array, error) {
+	listA, dimA, err := vectorColumn(a)
+	if err != nil {
+		return nil, err
+	}
+	listB, dimB, err := vectorColumn(b)
+	if err != nil {
+		return nil, err
+	}
+	if listA.Len() != listB.Len() {
+		return nil, fmt.Errorf("arrays have different lengths: %d and %d", listA.Len(), listB.Len())
+	}
+	if dimA != dimB {
+		return nil, fmt.Errorf("vectors have different dimensions: %d and %d", dimA, dimB)
+	}
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(listA.Len())
+
+	x, y := make([]float64, dimA), make([]float64, dimB)
+	for i := 0; i < listA.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if listA.IsNull(i) || listB.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		if err := vectorAt(listA, i, x); err != nil {
+			return nil, err
+		}
+		if err := vectorAt(listB, i, y); err != nil {
+			return nil, err
+		}
+		if v, ok := fn(x, y); ok {
+			builder.Append(v)
+		} else {
+			builder.AppendNull()
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// dot returns the dot product of two vectors of the same length
+func dot(x, y []float64) float64 {
+	var sum float64
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
+
+// l2Norm returns the Euclidean length of v
+func l2Norm(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// DistanceMetric selects how PairwiseDistance measures the distance between rows
+type DistanceMetric int
+
+const (
+	// EuclideanDistance is the straight-line distance
+	EuclideanDistance DistanceMetric = iota
+	// ManhattanDistance is the sum of absolute differences
+	ManhattanDistance
+	// CosineDistance is one minus the cosine similarity
+	CosineDistance
+)
+
+// DefaultPairwiseMaxRows is the input row limit PairwiseDistance applies when
+// PairwiseOptions.MaxRows is zero
+const DefaultPairwiseMaxRows int64 = 5_000
+
+// PairwiseOptions controls the size guard of PairwiseDistance
+type PairwiseOptions struct {
+	// MaxRows is the largest number of input rows allowed. Zero means
+	// DefaultPairwiseMaxRows and a negative value disables the limit.
+	MaxRows int64
+}
+
+// PairwiseDistance returns the distance matrix between the rows of rec, using
+// the numeric columns cols as each row's feature vector. The result is a
+// square record of Float64 columns named "0", "1", ... in which column j of
+// row i holds the distance from row i to row j. Distances involving a row
+// with a null feature are null, as are cosine distances involving an
+// all-zero row. The output grows quadratically, so inputs over the row limit
+// are an error.
+func PairwiseDistance(ctx context.Context, rec arrow.Record, cols []string, metric DistanceMetric, opts ...PairwiseOptions) (arrow.Record, error) {
+	if len(opts) > 1 {
+		return nil, fmt.Errorf("at most one options value may be given, got %d", len(opts))
+	}
+	maxRows := DefaultPairwiseMaxRows
+	if len(opts) == 1 && opts[0].MaxRows != 0 {
+		maxRows = opts[0].MaxRows
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("at least one feature column is required")
+	}
+
+	var distance func(x, y []float64) (float64, bool)
+	switch metric {
+	case EuclideanDistance:
+		distance = func(x, y []float64) (float64, bool) {
+			var sum float64
+			for k := range x {
+				d := x[k] - y[k]
+				sum += d * d
+			}
+			return math.Sqrt(sum), true
+		}
+	case ManhattanDistance:
+		distance = func(x, y []float64) (float64, bool) {
+			var sum float64
+			for k := range x {
+				sum += math.Abs(x[k] - y[k])
+			}
+			return sum, true
+		}
+	case CosineDistance:
+		distance = func(x, y []float64) (float64, bool) {
+			normX, normY := l2Norm(x), l2Norm(y)
+			if normX == 0 || normY == 0 {
+				return 0, false
+			}
+			return 1 - dot(x, y)/(normX*normY), true
+		}
+	default:
+		return nil, fmt.Errorf("unknown distance metric %d", metric)
+	}
+
+	numRows := rec.NumRows()
+	if maxRows > 0 && numRows > maxRows {
+		return nil, fmt.Errorf("pairwise distance of %d rows exceeds the limit of %d", numRows, maxRows)
+	}
+
+	features := make([]arrow.Array, len(cols))
+	for k, name := range cols {
+		idx, err := GetColumnIndex(rec, name)
+		if err != nil {
+			return nil, err
+		}
+		features[k] = rec.Column(idx)
+		if !isNumericType(features[k].DataType()) {
+			return nil, fmt.Errorf("column %s is not numeric: %s", name, features[k].DataType())
+		}
+	}
+
+	// Gather each row's feature vector, nil for rows with a null feature
+	vectors := make([][]float64, numRows)
+	for i := range vectors {
+		vec := make([]float64, len(features))
+		for k, col := range features {
+			if col.IsNull(i) {
+				vec = nil
+				break
+			}
+			vec[k], _ = floatAt(col, i)
+		}
+		vectors[i] = vec
+	}
+
+	fields := make([]arrow.Field, numRows)
+	columns := make([]arrow.Array, numRows)
+	for j := range columns {
+		if err := ctx.Err(); err != nil {
+			// Clean up already created columns
+			for k := 0; k < j; k++ {
+				columns[k].Release()
+			}
+			return nil, err
+		}
+
+		builder := array.NewFloat64Builder(memory.DefaultAllocator)
+		builder.Reserve(int(numRows))
+		for i := range vectors {
+			if vectors[i] == nil || vectors[j] == nil {
+				builder.AppendNull()
+				continue
+			}
+			if d, ok := distance(vectors[i], vectors[j]); ok {
+				builder.Append(d)
+			} else {
+				builder.AppendNull()
+			}
+		}
+		columns[j] = builder.NewArray()
+		builder.Release()
+		fields[j] = arrow.Field{Name: strconv.Itoa(j), Type: arrow.PrimitiveTypes.Float64, Nullable: true}
+	}
+
+	result := array.NewRecord(arrow.NewSchema(fields, nil), columns, numRows)
+	for _, col := range columns {
+		col.Release()
+	}
+	return result, nil
+}
+
+// vectorColumn checks that input is a fixed-size-list array with a numeric
+// value type and returns it with its dimension
+func vectorColumn(input arrow.Array) (*array.FixedSizeList, int, error) {
+	list, ok := input.(*array.FixedSizeList)
+	if !ok {
+		return nil, 0, fmt.Errorf("expected a fixed-size list array, got %s", input.DataType())
+	}
+	valueType := list.DataType().(*arrow.FixedSizeListType).Elem()
+	switch valueType.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+		arrow.FLOAT32, arrow.FLOAT64:
+	default:
+		return nil, 0, fmt.Errorf("unsupported vector value type %s", valueType)
+	}
+	return list, int(list.DataType().(*arrow.FixedSizeListType).Len()), nil
+}
+
+// vectorAt copies the elements of row i of the list into dst as float64
+func vectorAt(list *array.FixedSizeList, i int, dst []float64) error {
+	values := list.ListValues()
+	start, _ := list.ValueOffsets(i)
+	for j := range dst {
+		idx := int(start) + j
+		if values.IsNull(idx) {
+			return fmt.Errorf("row %d has a null element at position %d", i, j)
+		}
+		dst[j], _ = floatAt(values, idx)
+	}
+	return nil
+}
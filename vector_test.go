@@ -0,0 +1,159 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_meanVector() {
+	// Build a column of 3-dimensional embeddings with one null row
+	builder := array.NewFixedSizeListBuilder(memory.DefaultAllocator, 3, arrow.PrimitiveTypes.Float32)
+	defer builder.Release()
+	values := builder.ValueBuilder().(*array.Float32Builder)
+
+	builder.Append(true)
+	values.AppendValues([]float32{1, 0, 2}, nil)
+	builder.AppendNull()
+	builder.Append(true)
+	values.AppendValues([]float32{3, 4, 0}, nil)
+
+	embeddings := builder.NewArray()
+	defer embeddings.Release()
+
+	// Average the non-null rows into a centroid
+	ctx := context.Background()
+	centroid, err := archery.MeanVector(ctx, embeddings)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Centroid:", centroid)
+
+	// Output:
+	// Centroid: [2 2 1]
+}
+
+func Example_normalizeL2() {
+	// Build a column of 2-dimensional embeddings, including a zero vector
+	builder := array.NewFixedSizeListBuilder(memory.DefaultAllocator, 2, arrow.PrimitiveTypes.Float32)
+	defer builder.Release()
+	values := builder.ValueBuilder().(*array.Float32Builder)
+
+	builder.Append(true)
+	values.AppendValues([]float32{3, 4}, nil)
+	builder.Append(true)
+	values.AppendValues([]float32{0, 0}, nil)
+	builder.AppendNull()
+
+	embeddings := builder.NewArray()
+	defer embeddings.Release()
+
+	// Scale every row to unit length
+	ctx := context.Background()
+	normalized, err := archery.NormalizeL2(ctx, embeddings)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer normalized.Release()
+
+	fmt.Println("Type:", normalized.DataType())
+	fmt.Println("Normalized:", normalized)
+
+	// Output:
+	// Type: fixed_size_list<item: float32, nullable>[2]
+	// Normalized: [[0.6 0.8] [0 0] (null)]
+}
+
+func Example_cosineSimilarity() {
+	// buildVectors builds a column of 2-dimensional vectors, nil meaning null
+	buildVectors := func(rows ...[]float32) arrow.Array {
+		builder := array.NewFixedSizeListBuilder(memory.DefaultAllocator, 2, arrow.PrimitiveTypes.Float32)
+		defer builder.Release()
+		values := builder.ValueBuilder().(*array.Float32Builder)
+		for _, row := range rows {
+			if row == nil {
+				builder.AppendNull()
+				continue
+			}
+			builder.Append(true)
+			values.AppendValues(row, nil)
+		}
+		return builder.NewArray()
+	}
+
+	queries := buildVectors([]float32{1, 0}, []float32{1, 1}, []float32{0, 0}, nil)
+	defer queries.Release()
+	docs := buildVectors([]float32{2, 0}, []float32{-1, 1}, []float32{1, 2}, []float32{1, 1})
+	defer docs.Release()
+
+	ctx := context.Background()
+	dots, err := archery.DotProduct(ctx, queries, docs)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer dots.Release()
+	fmt.Println("Dot:", dots)
+
+	// The zero query has no direction, so its similarity is null
+	sims, err := archery.CosineSimilarity(ctx, queries, docs)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer sims.Release()
+	fmt.Println("Cosine:", sims)
+
+	// Output:
+	// Dot: [2 0 0 (null)]
+	// Cosine: [1 0 (null) (null)]
+}
+
+func Example_pairwiseDistance() {
+	// Create a record of 2-dimensional points
+	xBuilder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer xBuilder.Release()
+	xBuilder.AppendValues([]float64{0, 3, 0}, nil)
+	xs := xBuilder.NewArray()
+	defer xs.Release()
+
+	yBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer yBuilder.Release()
+	yBuilder.AppendValues([]int64{0, 4, 1}, nil)
+	ys := yBuilder.NewArray()
+	defer ys.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "x", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "y", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{xs, ys}, 3)
+	defer rec.Release()
+
+	ctx := context.Background()
+	dist, err := archery.PairwiseDistance(ctx, rec, []string{"x", "y"}, archery.EuclideanDistance)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer dist.Release()
+	for i, col := range dist.Columns() {
+		fmt.Printf("%s: %v\n", dist.ColumnName(i), col)
+	}
+
+	// Inputs over the row limit are rejected
+	_, err = archery.PairwiseDistance(ctx, rec, []string{"x", "y"}, archery.ManhattanDistance, archery.PairwiseOptions{MaxRows: 2})
+	fmt.Println("Error:", err)
+
+	// Output:
+	// 0: [0 5 1]
+	// 1: [5 0 4.242640687119285]
+	// 2: [1 4.242640687119285 0]
+	// Error: pairwise distance of 3 rows exceeds the limit of 2
+}
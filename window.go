@@ -0,0 +1,388 @@
+package archery
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// WINDOW OPERATIONS
+
+// EWMA returns the exponentially weighted moving average of a numeric array with
+// smoothing factor alpha in (0, 1]. The first non-null value seeds the average.
+// Null inputs carry the previous smoothed value forward, and positions before
+// the first non-null value are null. The result is a Float64 array.
+func EWMA(ctx context.Context, input arrow.Array, alpha float64) (arrow.Array, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("alpha must be in (0, 1], got %v", alpha)
+	}
+
+	floats, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("ewma: %w", err)
+	}
+	defer floats.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(floats.Len())
+
+	var smoothed float64
+	seeded := false
+	for i := 0; i < floats.Len(); i++ {
+		if floats.IsValid(i) {
+			if seeded {
+				smoothed = alpha*floats.Value(i) + (1-alpha)*smoothed
+			} else {
+				smoothed = floats.Value(i)
+				seeded = true
+			}
+		}
+		if !seeded {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(smoothed)
+	}
+	return builder.NewArray(), nil
+}
+
+// ExpandingStd returns the running population standard deviation of a numeric
+// array over all non-null values seen so far, matching StandardDeviation on
+// each prefix. It uses Welford's online algorithm for numerical stability.
+// Positions with fewer than minPeriods non-null values so far are null. The
+// result is a Float64 array.
+func ExpandingStd(ctx context.Context, input arrow.Array, minPeriods int) (arrow.Array, error) {
+	if minPeriods < 1 {
+		return nil, fmt.Errorf("min periods must be at least 1, got %d", minPeriods)
+	}
+
+	floats, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("expanding std: %w", err)
+	}
+	defer floats.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(floats.Len())
+
+	var count int
+	var mean, m2 float64
+	for i := 0; i < floats.Len(); i++ {
+		if floats.IsValid(i) {
+			count++
+			delta := floats.Value(i) - mean
+			mean += delta / float64(count)
+			m2 += delta * (floats.Value(i) - mean)
+		}
+		if count < minPeriods {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(math.Sqrt(m2 / float64(count)))
+	}
+	return builder.NewArray(), nil
+}
+
+// RollingCorrelation returns the Pearson correlation of a and b over a trailing
+// window of the given size at each position. Pairs where either side is null
+// are skipped within a window. The first window-1 positions are null, as are
+// windows with fewer than two pairs or with zero variance on either side. The
+// result is a Float64 array. Each window is computed in two passes, deviations
+// from the window means, so large offsets such as timestamps or prices do not
+// cancel out the variance.
+func RollingCorrelation(ctx context.Context, a, b arrow.Array, window int) (arrow.Array, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("window must be at least 2, got %d", window)
+	}
+	if a.Len() != b.Len() {
+		return nil, fmt.Errorf("arrays must have the same length, got %d and %d", a.Len(), b.Len())
+	}
+
+	xs, err := castFloat64(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("rolling correlation: %w", err)
+	}
+	defer xs.Release()
+
+	ys, err := castFloat64(ctx, b)
+	if err != nil {
+		return nil, fmt.Errorf("rolling correlation: %w", err)
+	}
+	defer ys.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(xs.Len())
+
+	for end := 0; end < xs.Len(); end++ {
+		if end < window-1 {
+			builder.AppendNull()
+			continue
+		}
+
+		start := end - window + 1
+		var n, sumX, sumY float64
+		for i := start; i <= end; i++ {
+			if xs.IsNull(i) || ys.IsNull(i) {
+				continue
+			}
+			n++
+			sumX += xs.Value(i)
+			sumY += ys.Value(i)
+		}
+		if n < 2 {
+			builder.AppendNull()
+			continue
+		}
+
+		// Sum the products of the deviations from the window means
+		meanX, meanY := sumX/n, sumY/n
+		var covariance, varX, varY float64
+		for i := start; i <= end; i++ {
+			if xs.IsNull(i) || ys.IsNull(i) {
+				continue
+			}
+			dx, dy := xs.Value(i)-meanX, ys.Value(i)-meanY
+			covariance += dx * dy
+			varX += dx * dx
+			varY += dy * dy
+		}
+		if varX == 0 || varY == 0 {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(covariance / math.Sqrt(varX*varY))
+	}
+	return builder.NewArray(), nil
+}
+
+// RollingSum returns the sum of the non-null values in a trailing window of
+// the given size at each position. The first window-1 positions are null, as
+// are windows with no non-null values. The result is a Float64 array.
+func RollingSum(ctx context.Context, input arrow.Array, window int) (arrow.Array, error) {
+	return rollingApply(ctx, input, window, "rolling sum", func(values []float64) float64 {
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	})
+}
+
+// RollingMean returns the mean of the non-null values in a trailing window of
+// the given size at each position, with nulls placed as in RollingSum
+func RollingMean(ctx context.Context, input arrow.Array, window int) (arrow.Array, error) {
+	return rollingApply(ctx, input, window, "rolling mean", func(values []float64) float64 {
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	})
+}
+
+// RollingStd returns the population standard deviation of the non-null values
+// in a trailing window of the given size at each position, matching
+// StandardDeviation on each window, with nulls placed as in RollingSum
+func RollingStd(ctx context.Context, input arrow.Array, window int) (arrow.Array, error) {
+	return rollingApply(ctx, input, window, "rolling std", func(values []float64) float64 {
+		var mean, m2 float64
+		for i, v := range values {
+			delta := v - mean
+			mean += delta / float64(i+1)
+			m2 += delta * (v - mean)
+		}
+		return math.Sqrt(m2 / float64(len(values)))
+	})
+}
+
+// rollingApply reduces the non-null values of each trailing window with fn
+func rollingApply(ctx context.Context, input arrow.Array, window int, name string, fn func(values []float64) float64) (arrow.Array, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("window must be at least 1, got %d", window)
+	}
+
+	floats, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer floats.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(floats.Len())
+
+	values := make([]float64, 0, window)
+	for end := 0; end < floats.Len(); end++ {
+		if end < window-1 {
+			builder.AppendNull()
+			continue
+		}
+
+		values = values[:0]
+		for i := end - window + 1; i <= end; i++ {
+			if floats.IsValid(i) {
+				values = append(values, floats.Value(i))
+			}
+		}
+		if len(values) == 0 {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(fn(values))
+	}
+	return builder.NewArray(), nil
+}
+
+// PctChange returns the fractional change x[i]/x[i-periods] - 1 at each
+// position. The first periods positions are null, as are positions where
+// either value is null or the earlier value is zero. The result is a Float64
+// array.
+func PctChange(ctx context.Context, input arrow.Array, periods int) (arrow.Array, error) {
+	return periodRatio(ctx, input, periods, "pct change", func(ratio float64) (float64, bool) {
+		return ratio - 1, true
+	})
+}
+
+// LogReturn returns the log return ln(x[i]/x[i-periods]) at each position, with
+// nulls placed as in PctChange. Positions where the ratio is not positive are
+// also null. The result is a Float64 array.
+func LogReturn(ctx context.Context, input arrow.Array, periods int) (arrow.Array, error) {
+	return periodRatio(ctx, input, periods, "log return", func(ratio float64) (float64, bool) {
+		return math.Log(ratio), ratio > 0
+	})
+}
+
+// periodRatio maps the ratio of each value to the value periods positions
+// earlier through fn, producing null where fn reports false
+func periodRatio(ctx context.Context, input arrow.Array, periods int, name string, fn func(ratio float64) (float64, bool)) (arrow.Array, error) {
+	if periods < 1 {
+		return nil, fmt.Errorf("periods must be at least 1, got %d", periods)
+	}
+
+	floats, err := castFloat64(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer floats.Release()
+
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(floats.Len())
+
+	for i := 0; i < floats.Len(); i++ {
+		prev := i - periods
+		if prev < 0 || floats.IsNull(i) || floats.IsNull(prev) || floats.Value(prev) == 0 {
+			builder.AppendNull()
+			continue
+		}
+		v, ok := fn(floats.Value(i) / floats.Value(prev))
+		if !ok {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(v)
+	}
+	return builder.NewArray(), nil
+}
+
+// CumulativeCountDistinct returns an Int64 array holding, at each position,
+// the number of distinct non-null values seen so far. It supports the types
+// UniqueStable does; as with Go maps, each float NaN counts as a new value.
+func CumulativeCountDistinct(ctx context.Context, input arrow.Array) (arrow.Array, error) {
+	first, err := firstOccurrences(input)
+	if err != nil {
+		return nil, fmt.Errorf("cumulative count distinct: %w", err)
+	}
+
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.Reserve(input.Len())
+
+	var distinct int64
+	for i, isFirst := range first {
+		if isFirst && input.IsValid(i) {
+			distinct++
+		}
+		builder.Append(distinct)
+	}
+	return builder.NewArray(), nil
+}
+
+// RECORD WINDOW OPERATIONS
+
+// RollingRecord computes trailing-window aggregates over columns of a record
+// whose rows are already in the desired order. Each aggregate is appended to
+// the handle's record, so successive calls accumulate columns. The handle
+// holds a reference to its record and must be released.
+type RollingRecord struct {
+	rec    arrow.Record
+	window int
+}
+
+// Rolling returns a handle computing trailing-window aggregates of the given
+// size over the record's columns. The window must be at least 1.
+func Rolling(rec arrow.Record, window int) (*RollingRecord, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("window must be at least 1, got %d", window)
+	}
+	rec.Retain()
+	return &RollingRecord{rec: rec, window: window}, nil
+}
+
+// Sum appends a col_rolling_sum column, as computed by RollingSum, and returns
+// the record with every column appended so far
+func (r *RollingRecord) Sum(ctx context.Context, col string) (arrow.Record, error) {
+	return r.apply(ctx, col, "sum", RollingSum)
+}
+
+// Mean appends a col_rolling_mean column, as computed by RollingMean, and
+// returns the record with every column appended so far
+func (r *RollingRecord) Mean(ctx context.Context, col string) (arrow.Record, error) {
+	return r.apply(ctx, col, "mean", RollingMean)
+}
+
+// Std appends a col_rolling_std column, as computed by RollingStd, and returns
+// the record with every column appended so far
+func (r *RollingRecord) Std(ctx context.Context, col string) (arrow.Record, error) {
+	return r.apply(ctx, col, "std", RollingStd)
+}
+
+// Release releases the handle's reference to its record. Records returned by
+// Sum, Mean and Std stay valid until the caller releases them.
+func (r *RollingRecord) Release() {
+	ReleaseRecord(r.rec)
+	r.rec = nil
+}
+
+// apply appends the rolling aggregate of a column under a suffixed name to the
+// handle's record and returns a new reference to the result
+func (r *RollingRecord) apply(ctx context.Context, col, suffix string, fn func(context.Context, arrow.Array, int) (arrow.Array, error)) (arrow.Record, error) {
+	input, err := GetColumn(r.rec, col)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Release()
+
+	result, err := fn(ctx, input, r.window)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", col, err)
+	}
+	defer result.Release()
+
+	field := arrow.Field{Name: col + "_rolling_" + suffix, Type: arrow.PrimitiveTypes.Float64, Nullable: true}
+	appended, err := AppendColumn(r.rec, field, result)
+	if err != nil {
+		return nil, err
+	}
+	r.rec.Release()
+	r.rec = appended
+	appended.Retain()
+	return appended, nil
+}
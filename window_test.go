@@ -0,0 +1,319 @@
+package archery_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/TFMV/archery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func Example_ewma() {
+	// Create a noisy series with a gap
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{10, 20, 0, 10}, []bool{true, true, false, true})
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Smooth with alpha = 0.5
+	ctx := context.Background()
+	smoothed, err := archery.EWMA(ctx, arr, 0.5)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(smoothed)
+
+	// Print the result
+	fmt.Println(smoothed)
+
+	// Alpha must be in (0, 1], which NaN is not
+	_, err = archery.EWMA(ctx, arr, math.NaN())
+	fmt.Println("Error:", err)
+
+	// Output:
+	// [10 15 15 12.5]
+	// Error: alpha must be in (0, 1], got NaN
+}
+
+func Example_expandingStd() {
+	// Create a test series
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{2, 4, 4, 4, 5, 5, 7, 9}, nil)
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Require at least three observations
+	ctx := context.Background()
+	std, err := archery.ExpandingStd(ctx, arr, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(std)
+
+	// Print the running standard deviation
+	for i, v := range archery.Values(std.(*array.Float64)) {
+		if v == nil {
+			fmt.Println(i, "null")
+			continue
+		}
+		fmt.Printf("%d %.2f\n", i, *v)
+	}
+
+	// Output:
+	// 0 null
+	// 1 null
+	// 2 0.94
+	// 3 0.87
+	// 4 0.98
+	// 5 1.00
+	// 6 1.40
+	// 7 2.00
+}
+
+func Example_rollingCorrelation() {
+	// Create two metrics that move together, then diverge
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1, 2, 3, 4, 5, 6}, nil)
+	a := builder.NewFloat64Array()
+	defer a.Release()
+	builder.AppendValues([]float64{2, 4, 6, 8, 6, 4}, nil)
+	b := builder.NewFloat64Array()
+	defer b.Release()
+
+	// Correlate over a trailing window of three
+	ctx := context.Background()
+	corr, err := archery.RollingCorrelation(ctx, a, b, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(corr)
+
+	// Print the result
+	for i, v := range archery.Values(corr.(*array.Float64)) {
+		if v == nil {
+			fmt.Println(i, "null")
+			continue
+		}
+		fmt.Printf("%d %.2f\n", i, *v)
+	}
+
+	// Output:
+	// 0 null
+	// 1 null
+	// 2 1.00
+	// 3 1.00
+	// 4 0.00
+	// 5 -1.00
+}
+
+func Example_rollingCorrelationLargeOffset() {
+	// The same metrics as epoch-scale values, where summing squares of the
+	// raw values would lose every significant digit of the variance
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	for _, v := range []float64{1, 2, 3, 4, 5, 6} {
+		builder.Append(1e9 + v)
+	}
+	a := builder.NewFloat64Array()
+	defer a.Release()
+	for _, v := range []float64{2, 4, 6, 8, 6, 4} {
+		builder.Append(1e9 + v)
+	}
+	b := builder.NewFloat64Array()
+	defer b.Release()
+
+	ctx := context.Background()
+	corr, err := archery.RollingCorrelation(ctx, a, b, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer archery.ReleaseArray(corr)
+
+	// The correlations match those of the unshifted metrics
+	for i, v := range archery.Values(corr.(*array.Float64)) {
+		if v == nil {
+			fmt.Println(i, "null")
+			continue
+		}
+		fmt.Printf("%d %.6f\n", i, *v)
+	}
+
+	// Output:
+	// 0 null
+	// 1 null
+	// 2 1.000000
+	// 3 1.000000
+	// 4 0.000000
+	// 5 -1.000000
+}
+
+func Example_rollingMean() {
+	// Create a test array with a gap
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{1, 2, 3, 0, 5}, []bool{true, true, true, false, true})
+	arr := builder.NewFloat64Array()
+	defer arr.Release()
+
+	// Three-value trailing windows skip the null
+	ctx := context.Background()
+	means, err := archery.RollingMean(ctx, arr, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer means.Release()
+
+	sums, err := archery.RollingSum(ctx, arr, 3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer sums.Release()
+
+	fmt.Println("Mean:", means)
+	fmt.Println("Sum:", sums)
+
+	// Output:
+	// Mean: [(null) (null) 2 2.5 4]
+	// Sum: [(null) (null) 6 5 8]
+}
+
+func Example_pctChange() {
+	// Daily closing prices with a missing day
+	builder := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]float64{100, 110, 99, 0, 120}, []bool{true, true, true, false, true})
+	prices := builder.NewFloat64Array()
+	defer prices.Release()
+
+	ctx := context.Background()
+	changes, err := archery.PctChange(ctx, prices, 1)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer changes.Release()
+
+	returns, err := archery.LogReturn(ctx, prices, 2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer returns.Release()
+
+	fmt.Println("Daily change:")
+	for i := 0; i < changes.Len(); i++ {
+		if changes.IsNull(i) {
+			fmt.Println("  null")
+			continue
+		}
+		fmt.Printf("  %.3f\n", changes.(*array.Float64).Value(i))
+	}
+	fmt.Println("Two-day log return:")
+	for i := 0; i < returns.Len(); i++ {
+		if returns.IsNull(i) {
+			fmt.Println("  null")
+			continue
+		}
+		fmt.Printf("  %.3f\n", returns.(*array.Float64).Value(i))
+	}
+
+	// Output:
+	// Daily change:
+	//   null
+	//   0.100
+	//   -0.100
+	//   null
+	//   null
+	// Two-day log return:
+	//   null
+	//   null
+	//   -0.010
+	//   null
+	//   0.192
+}
+
+func Example_cumulativeCountDistinct() {
+	// An ordered stream of user IDs with a missing value
+	builder := array.NewStringBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]string{"ann", "bob", "ann", "", "cy", "bob"}, []bool{true, true, true, false, true, true})
+	users := builder.NewArray()
+	defer users.Release()
+
+	// Count distinct users seen so far at each event
+	ctx := context.Background()
+	growth, err := archery.CumulativeCountDistinct(ctx, users)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer growth.Release()
+
+	fmt.Println("Distinct users:", growth)
+
+	// Output:
+	// Distinct users: [1 2 2 2 3 3]
+}
+
+func Example_rolling() {
+	// Create a record of daily metrics
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	defer builder.Release()
+	builder.AppendValues([]int64{10, 20, 30, 40}, nil)
+	visits := builder.NewArray()
+	defer visits.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "visits", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{visits}, 4)
+	defer rec.Release()
+
+	// Append a two-day moving average, then a moving standard deviation
+	ctx := context.Background()
+	rolling, err := archery.Rolling(rec, 2)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer rolling.Release()
+
+	withMean, err := rolling.Mean(ctx, "visits")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer withMean.Release()
+
+	// The second call builds on the first, keeping the mean column
+	withStd, err := rolling.Std(ctx, "visits")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer withStd.Release()
+
+	// A window below one is rejected up front
+	_, err = archery.Rolling(rec, 0)
+
+	fmt.Println("Columns:", archery.ColumnNames(withStd))
+	fmt.Println("Mean:", withStd.Column(1))
+	fmt.Println("Std:", withStd.Column(2))
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Columns: [visits visits_rolling_mean visits_rolling_std]
+	// Mean: [(null) 15 25 35]
+	// Std: [(null) 5 5 5]
+	// Error: window must be at least 1, got 0
+}